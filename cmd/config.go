@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect lv configuration",
+	Long: `lv reads defaults from ~/.config/lv/config.toml (or config.yaml), then from the
+nearest .lv.toml (or .lv.yaml) in the current directory or its parents.
+Command line flags override both.`,
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the effective configuration",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, files, err := loadConfig(cmd)
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cfg.Print(os.Stdout, files); err != nil {
+			fmt.Printf("Error printing config: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configPrintCmd)
+}
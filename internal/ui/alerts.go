@@ -0,0 +1,334 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+const (
+	// maxAlertHits bounds the alerts panel; older hits are dropped.
+	maxAlertHits = 500
+	// alertFlashDuration is how long the footer flashes after an alert.
+	alertFlashDuration = 2 * time.Second
+	// alertBellDuration keeps the bell in the view long enough for the
+	// renderer to draw a frame with it.
+	alertBellDuration = 100 * time.Millisecond
+)
+
+// alertRule is a compiled [[alert]] rule.
+type alertRule struct {
+	config.Alert
+	re       *regexp.Regexp // nil matches any line
+	level    logLevel
+	hasLevel bool
+}
+
+// alertRules are the configured rules, in config order.
+var alertRules []alertRule
+
+// alertNow is the clock for rate thresholds, replaced in tests.
+var alertNow = time.Now
+
+// setAlertRules compiles the configured alert rules.
+func setAlertRules(alerts []config.Alert) {
+	alertRules = nil
+	levels := map[string]logLevel{"error": levelError, "warn": levelWarn, "info": levelInfo, "debug": levelDebug}
+	for _, a := range alerts {
+		r := alertRule{Alert: a}
+		if a.Pattern != "" {
+			re, err := regexp.Compile(a.Pattern)
+			if err != nil {
+				continue
+			}
+			r.re = re
+		}
+		r.level, r.hasLevel = levels[a.Level]
+		if r.Name == "" {
+			r.Name = strings.TrimSpace(a.Level + " " + a.Pattern)
+		}
+		alertRules = append(alertRules, r)
+	}
+}
+
+func (r alertRule) matches(line string) bool {
+	if r.hasLevel && !hasLevel(line, r.level) {
+		return false
+	}
+	return r.re == nil || r.re.MatchString(line)
+}
+
+// alertHit is one firing of a rule.
+type alertHit struct {
+	rule  string
+	at    time.Time
+	store *lineStore
+	index int // Line index in the store
+	line  string
+}
+
+// alertState is shared by every tab and pane of the window.
+type alertState struct {
+	hits     []alertHit
+	recent   map[int][]time.Time // Rule index -> match times inside its window
+	unseen   int                 // Hits since the panel was last opened
+	flash    int                 // Generation of the latest footer flash
+	flashing bool
+	ringing  bool // The view carries the bell
+}
+
+func newAlertState() *alertState {
+	return &alertState{recent: make(map[int][]time.Time)}
+}
+
+// alertFlashDoneMsg ends the footer flash of generation gen.
+type alertFlashDoneMsg struct{ gen int }
+
+// alertBellDoneMsg takes the bell of generation gen out of the view.
+type alertBellDoneMsg struct{ gen int }
+
+// alertCommandMsg reports a failed alert command.
+type alertCommandMsg struct {
+	rule string
+	err  error
+}
+
+// followed reports whether a pane of any tab follows store s.
+func (m *Model) followed(s *lineStore) bool {
+	for _, v := range m.views() {
+		if v.store == s && v.following {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAlerts runs the alert rules over the lines of s from index first on,
+// when s is being followed. A firing rule rings the bell, flashes the footer,
+// bookmarks the line in the views of s and runs the rule's command.
+func (m *Model) checkAlerts(s *lineStore, first int) tea.Cmd {
+	if len(alertRules) == 0 || s.generated || !m.followed(s) {
+		return nil
+	}
+	st := m.alerts
+	now := alertNow()
+
+	var fired []alertHit
+	bell := false
+	ran := make(map[int]bool)
+	var cmds []tea.Cmd
+	for ri, r := range alertRules {
+		for i := first; i < len(s.lines); i++ {
+			line := s.lines[i]
+			if !r.matches(line) {
+				continue
+			}
+			if r.Count > 1 {
+				recent := st.recent[ri]
+				for len(recent) > 0 && now.Sub(recent[0]) > r.Window.Duration {
+					recent = recent[1:]
+				}
+				recent = append(recent, now)
+				if len(recent) < r.Count {
+					st.recent[ri] = recent
+					continue
+				}
+				st.recent[ri] = nil
+			}
+			hit := alertHit{rule: r.Name, at: now, store: s, index: i, line: line}
+			fired = append(fired, hit)
+			bell = bell || !r.Quiet
+			if r.Command != "" && !ran[ri] {
+				ran[ri] = true // Once per rule and batch
+				cmds = append(cmds, runAlertCommand(r.Command, hit))
+			}
+		}
+	}
+	if len(fired) == 0 {
+		return nil
+	}
+
+	st.hits = append(st.hits, fired...)
+	if over := len(st.hits) - maxAlertHits; over > 0 {
+		st.hits = st.hits[over:]
+	}
+	st.unseen += len(fired)
+	// Views that have not caught up yet, in background tabs or paused, get
+	// the bookmarks when they sync.
+	for _, v := range m.views() {
+		if v.store == s && len(v.originalLines) == len(s.lines) {
+			for _, h := range fired {
+				v.bookmarkStoreLine(h.index)
+			}
+		}
+	}
+
+	last := fired[len(fired)-1]
+	m.statusMsg = fmt.Sprintf("⚠ %s: %s", last.rule, strings.TrimSpace(stripAnsi(last.line)))
+	st.flash++
+	st.flashing = true
+	gen := st.flash
+	cmds = append(cmds, tea.Tick(alertFlashDuration, func(time.Time) tea.Msg { return alertFlashDoneMsg{gen} }))
+	if bell {
+		// The bell goes out with the next frame, written by the renderer
+		// like the rest of the screen.
+		st.ringing = true
+		cmds = append(cmds, tea.Tick(alertBellDuration, func(time.Time) tea.Msg { return alertBellDoneMsg{gen} }))
+	}
+	return tea.Batch(cmds...)
+}
+
+// runAlertCommand runs a rule's command through the shell.
+func runAlertCommand(command string, hit alertHit) tea.Cmd {
+	return func() tea.Msg {
+		c := exec.Command("sh", "-c", command)
+		c.Env = append(os.Environ(),
+			"LV_ALERT="+hit.rule,
+			"LV_SOURCE="+hit.store.name,
+			"LV_LINE="+stripAnsi(hit.line),
+		)
+		if out, err := c.CombinedOutput(); err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				err = fmt.Errorf("%w: %s", err, msg)
+			}
+			return alertCommandMsg{hit.rule, err}
+		}
+		return nil
+	}
+}
+
+// bookmarkAlerts bookmarks the alert lines from store line first on, which
+// the view shows after catching up.
+func (m *Model) bookmarkAlerts(first int) {
+	for _, h := range m.alerts.hits {
+		if h.store == m.store && h.index >= first {
+			m.bookmarkStoreLine(h.index)
+		}
+	}
+}
+
+// bookmarkStoreLine bookmarks the row showing store line idx, if visible.
+func (m *Model) bookmarkStoreLine(idx int) {
+	if row, ok := m.storeLineRow(idx); ok {
+		m.bookmarks[row] = struct{}{}
+	}
+}
+
+// storeLineRow finds the row showing store line idx. Rows are searched from
+// the end, where new lines are.
+func (m *Model) storeLineRow(idx int) (int, bool) {
+	sorted := m.tableMode && m.tableSortCol != ""
+	for row := len(m.filteredRefs) - 1; row >= 0; row-- {
+		ref := m.filteredRefs[row]
+		if ref.first <= idx && idx <= ref.last {
+			return row, true
+		}
+		if !sorted && ref.last < idx {
+			break
+		}
+	}
+	return 0, false
+}
+
+// handleAlertMsg ends footer flashes and bells, and reports failed alert
+// commands.
+func (m *Model) handleAlertMsg(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case alertFlashDoneMsg:
+		if msg.gen == m.alerts.flash {
+			m.alerts.flashing = false
+		}
+		return true
+	case alertBellDoneMsg:
+		if msg.gen == m.alerts.flash {
+			m.alerts.ringing = false
+		}
+		return true
+	case alertCommandMsg:
+		m.statusMsg = fmt.Sprintf("Alert %s: command failed: %v", msg.rule, msg.err)
+		return true
+	}
+	return false
+}
+
+// toggleAlerts opens or closes the alerts panel.
+func (m *Model) toggleAlerts() {
+	m.showAlerts = !m.showAlerts
+	m.alertCursor = 0
+	m.alerts.unseen = 0
+}
+
+// handleAlertsKey moves through the alerts panel; enter jumps to the line.
+func (m *Model) handleAlertsKey(msg tea.KeyMsg) {
+	hits := m.alerts.hits
+	switch {
+	case key.Matches(msg, m.keys.Alerts, m.keys.Cancel, m.keys.Quit):
+		m.showAlerts = false
+	case key.Matches(msg, m.keys.Down):
+		m.alertCursor = min(m.alertCursor+1, max(0, len(hits)-1))
+	case key.Matches(msg, m.keys.Up):
+		m.alertCursor = max(0, m.alertCursor-1)
+	case msg.Type == tea.KeyEnter:
+		if len(hits) > 0 {
+			m.jumpToAlert(hits[len(hits)-1-m.alertCursor])
+		}
+	}
+}
+
+// jumpToAlert shows the line of an alert, focusing the pane or selecting the
+// tab of its source.
+func (m *Model) jumpToAlert(hit alertHit) {
+	if m.store != hit.store && len(m.split) > 0 && m.split[0].store == hit.store {
+		m.focusOtherPane()
+	}
+	if m.store != hit.store {
+		for i, t := range m.tabs {
+			if i != m.activeTab && t.store == hit.store {
+				m.selectTab(i)
+				break
+			}
+		}
+	}
+	if m.store != hit.store {
+		m.statusMsg = "The source of this alert is no longer open"
+		return
+	}
+	m.showAlerts = false
+	if m.following {
+		m.setFollowing(false)
+	}
+	m.gotoLine(hit.index + 1)
+}
+
+// alertsView lists the alerts, newest first.
+func (m Model) alertsView() string {
+	hits := m.alerts.hits
+	rows := []string{helpKeyStyle.Bold(true).Render(fmt.Sprintf(" Alerts (%d)", len(hits))) +
+		mutedStyle.Render("  j/k select • enter go to line • esc close")}
+	if len(hits) == 0 {
+		rows = append(rows, mutedStyle.Render("  No alerts yet. Rules are [[alert]] entries in the config and fire while following."))
+		return strings.Join(rows, "\n")
+	}
+
+	cut := lipgloss.NewStyle().MaxWidth(m.screenWidth)
+	height := max(1, m.viewport.Height-1)
+	top := max(0, m.alertCursor-height+1)
+	for i := top; i < len(hits) && i < top+height; i++ {
+		h := hits[len(hits)-1-i]
+		row := cut.Render(fmt.Sprintf(" %s  %-16s %-14s %s", h.at.Format("15:04:05"), h.rule, filepath.Base(h.store.name), strings.TrimSpace(stripAnsi(h.line))))
+		if i == m.alertCursor {
+			row = selectedStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+	return strings.Join(rows, "\n")
+}
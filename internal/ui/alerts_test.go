@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestAlertsWhileFollowing(t *testing.T) {
+	m := resize(InitialModel("test.log", numberedLines(5), nil), 80, 24)
+	setAlertRules([]config.Alert{
+		{Name: "panic", Pattern: `panic:`},
+		{Name: "5xx burst", Pattern: `status=5\d\d`, Count: 3, Window: config.Duration{Duration: time.Minute}},
+	})
+	defer setAlertRules(nil)
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	alertNow = func() time.Time { return now }
+	defer func() { alertNow = time.Now }()
+
+	send := func(lines ...string) Model {
+		updated, _ := m.Update(LogChunkMsg{Lines: lines})
+		return updated.(Model)
+	}
+
+	m = send("INFO panic: but not following")
+	if len(m.alerts.hits) != 0 {
+		t.Fatal("Expected no alerts while not following")
+	}
+
+	m.following = true
+	m = send("INFO status=200", "ERROR panic: nil map", "INFO status=503")
+	if len(m.alerts.hits) != 1 || !m.alerts.flashing || !strings.Contains(m.statusMsg, "panic: nil map") {
+		t.Fatalf("Expected the panic rule to fire, got %d hits, status %q", len(m.alerts.hits), m.statusMsg)
+	}
+	if _, ok := m.bookmarks[7]; !ok {
+		t.Error("Expected the panic line to be bookmarked")
+	}
+
+	if !strings.HasPrefix(m.View(), "\a") {
+		t.Error("Expected the bell to be drawn with the next frame")
+	}
+
+	updated, _ := m.Update(alertFlashDoneMsg{m.alerts.flash})
+	m = updated.(Model)
+	if m.alerts.flashing {
+		t.Error("Expected the flash to end")
+	}
+	updated, _ = m.Update(alertBellDoneMsg{m.alerts.flash})
+	m = updated.(Model)
+	if strings.Contains(m.View(), "\a") {
+		t.Error("Expected the bell to leave the view")
+	}
+
+	now = now.Add(2 * time.Minute) // The first 503 is out of the window
+	m = send("INFO status=500", "INFO status=502")
+	if len(m.alerts.hits) != 1 {
+		t.Fatal("Expected no burst alert for two matches in the window")
+	}
+	now = now.Add(10 * time.Second)
+	m = send("INFO status=504")
+	if len(m.alerts.hits) != 2 || m.alerts.hits[1].rule != "5xx burst" || m.alerts.unseen != 2 {
+		t.Fatalf("Expected the third 5xx within a minute to fire, got %d hits", len(m.alerts.hits))
+	}
+
+	m = pressKeys(m, "A")
+	if !m.showAlerts || m.alerts.unseen != 0 || !strings.Contains(m.bodyView(), "5xx burst") {
+		t.Fatal("Expected the alerts panel to list the hits")
+	}
+	m = pressKeys(m, "j", "enter")
+	if m.showAlerts || m.filteredLines[m.cursor] != "ERROR panic: nil map" {
+		t.Errorf("Expected enter to jump to the panic line, got %q", m.filteredLines[m.cursor])
+	}
+}
+
+func TestAlertBookmarksBackgroundTabs(t *testing.T) {
+	m := resize(InitialModel("test.log", numberedLines(5), nil), 80, 24)
+	setAlertRules([]config.Alert{{Name: "panic", Pattern: `panic:`, Quiet: true}})
+	defer setAlertRules(nil)
+
+	m.following = true
+	m = runCommandLine(m, "tabnew")
+	updated, _ := m.Update(LogChunkMsg{Lines: []string{"ERROR panic: nil map"}})
+	m = updated.(Model)
+	if _, ok := m.bookmarks[5]; !ok {
+		t.Fatal("Expected the alert line to be bookmarked in the active tab")
+	}
+
+	m = pressKeys(m, "g", "t")
+	if m.activeTab != 0 || m.filteredLines[5] != "ERROR panic: nil map" {
+		t.Fatalf("Expected the first tab to catch up, got tab %d", m.activeTab)
+	}
+	if _, ok := m.bookmarks[5]; !ok {
+		t.Error("Expected the alert line to be bookmarked in the background tab too")
+	}
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ClipboardMode controls when copies are sent through the terminal using
+// OSC 52 in addition to (or instead of) the system clipboard.
+type ClipboardMode string
+
+const (
+	// ClipboardAuto uses OSC 52 in ssh sessions, or when the system clipboard
+	// is unavailable (no xclip/xsel, containers...).
+	ClipboardAuto ClipboardMode = "auto"
+	// ClipboardForce always emits OSC 52.
+	ClipboardForce ClipboardMode = "force"
+	// ClipboardOff only ever uses the system clipboard.
+	ClipboardOff ClipboardMode = "off"
+)
+
+const (
+	// maxOSC52Bytes is a conservative encoded payload size most terminals
+	// accept.
+	maxOSC52Bytes = 74994
+	// osc52Duration is how long a copy stays in the view, long enough for
+	// the renderer to write one frame.
+	osc52Duration = 100 * time.Millisecond
+)
+
+// Swapped out in tests.
+var systemClipboardWrite = clipboard.WriteAll
+
+// osc52DoneMsg takes the OSC 52 copy of generation gen out of the view.
+type osc52DoneMsg struct{ gen int }
+
+func isRemoteSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// osc52Sequence is the escape sequence that puts text on the terminal
+// clipboard, wrapped for tmux and screen so it reaches the outer terminal.
+func osc52Sequence(text string) (string, error) {
+	if n := base64.StdEncoding.EncodedLen(len(text)); n > maxOSC52Bytes {
+		return "", fmt.Errorf("%d bytes is too large for OSC 52", len(text))
+	}
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	return seq.String(), nil
+}
+
+// writeClipboard copies text according to mode and returns how it was
+// copied. A copy through the terminal is returned as the OSC 52 sequence to
+// write, which the caller hands to the renderer.
+func writeClipboard(text string, mode ClipboardMode) (method, seq string, err error) {
+	if mode == "" {
+		mode = ClipboardAuto
+	}
+
+	switch mode {
+	case ClipboardOff:
+		if err := systemClipboardWrite(text); err != nil {
+			return "", "", err
+		}
+		return "system clipboard", "", nil
+	case ClipboardForce:
+		seq, err := osc52Sequence(text)
+		return "OSC 52", seq, err
+	}
+
+	// Auto: a local clipboard is useless to a remote user, so prefer the
+	// terminal there; otherwise fall back to it only when needed.
+	if isRemoteSession() {
+		seq, err := osc52Sequence(text)
+		return "OSC 52", seq, err
+	}
+	sysErr := systemClipboardWrite(text)
+	if sysErr == nil {
+		return "system clipboard", "", nil
+	}
+	if seq, err = osc52Sequence(text); err != nil {
+		return "", "", fmt.Errorf("%v; %v", sysErr, err)
+	}
+	return "OSC 52", seq, nil
+}
+
+// copyToClipboard copies text and reports the outcome in the footer. A copy
+// through the terminal goes out with the next frame (see clearOSC52).
+func (m *Model) copyToClipboard(text string) {
+	method, seq, err := writeClipboard(text, m.clipboardMode)
+	if err != nil {
+		m.statusMsg = "Copy failed: " + err.Error()
+		return
+	}
+	if seq != "" {
+		m.osc52 = seq
+		m.osc52Gen++
+	}
+	lines := strings.Count(text, "\n") + 1
+	m.statusMsg = fmt.Sprintf("Copied %d %s, %d chars (%s)", lines, plural(lines, "line", "lines"), len([]rune(text)), method)
+}
+
+// clearOSC52 takes a copy made since generation gen out of the view once
+// the renderer has written it.
+func (m Model) clearOSC52(gen int) tea.Cmd {
+	if m.osc52Gen == gen {
+		return nil
+	}
+	gen = m.osc52Gen
+	return tea.Tick(osc52Duration, func(time.Time) tea.Msg { return osc52DoneMsg{gen} })
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stubClipboard replaces the system clipboard for a test.
+func stubClipboard(t *testing.T, sysErr error) *string {
+	t.Helper()
+	orig := systemClipboardWrite
+	t.Cleanup(func() { systemClipboardWrite = orig })
+
+	var copied string
+	systemClipboardWrite = func(s string) error {
+		if sysErr != nil {
+			return sysErr
+		}
+		copied = s
+		return nil
+	}
+	return &copied
+}
+
+func TestWriteClipboardModes(t *testing.T) {
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("SSH_CONNECTION", "")
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	copied := stubClipboard(t, nil)
+	if method, seq, err := writeClipboard("hello", ClipboardAuto); err != nil || method != "system clipboard" || seq != "" {
+		t.Errorf("auto with working clipboard: got %q, %q, %v", method, seq, err)
+	}
+	if *copied != "hello" {
+		t.Errorf("Expected system clipboard, got copied=%q", *copied)
+	}
+
+	stubClipboard(t, errors.New("no xclip"))
+	method, seq, err := writeClipboard("hello", ClipboardAuto)
+	if err != nil || method != "OSC 52" {
+		t.Errorf("auto fallback: got %q, %v", method, err)
+	}
+	payload := base64.StdEncoding.EncodeToString([]byte("hello"))
+	if !strings.Contains(seq, "\x1b]52;c;"+payload) {
+		t.Errorf("Expected OSC 52 sequence, got %q", seq)
+	}
+
+	stubClipboard(t, errors.New("no xclip"))
+	if _, _, err := writeClipboard("hello", ClipboardOff); err == nil {
+		t.Error("Expected off mode to report the system clipboard error")
+	}
+
+	// The limit is on the encoded payload, a third larger than the text.
+	if _, _, err := writeClipboard(strings.Repeat("x", maxOSC52Bytes*3/4+1), ClipboardForce); err == nil {
+		t.Error("Expected a payload over the OSC 52 limit once encoded to be refused")
+	}
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	copied = stubClipboard(t, nil)
+	if _, seq, err = writeClipboard("hello", ClipboardForce); err != nil {
+		t.Fatal(err)
+	}
+	if *copied != "" || !strings.HasPrefix(seq, "\x1bPtmux;") {
+		t.Errorf("Expected tmux-wrapped OSC 52 only, got %q", seq)
+	}
+}
+
+func TestCopyReportsStatus(t *testing.T) {
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("SSH_CONNECTION", "")
+	copied := stubClipboard(t, nil)
+
+	m := InitialModel("test.log", []string{"first line", "second"}, nil)
+	m.viewport.Height = 10
+	m = pressKeys(m, "V", "j", "y")
+
+	if *copied != "first line\nsecond" {
+		t.Errorf("Unexpected copied text %q", *copied)
+	}
+	if m.statusMsg != "Copied 2 lines, 17 chars (system clipboard)" {
+		t.Errorf("Unexpected status %q", m.statusMsg)
+	}
+
+	stubClipboard(t, errors.New("exec: \"xclip\": not found"))
+	m.clipboardMode = ClipboardOff
+	m = pressKeys(m, "y")
+	if !strings.HasPrefix(m.statusMsg, "Copy failed: ") {
+		t.Errorf("Expected failure status, got %q", m.statusMsg)
+	}
+}
+
+func TestOSC52GoesThroughTheView(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+	m := resize(InitialModel("test.log", []string{"first line"}, nil), 80, 10)
+	m.clipboardMode = ClipboardForce
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte("first line"))
+	if !strings.HasPrefix(m.View(), seq) || cmd == nil {
+		t.Fatalf("Expected the copy to go out with the next frame, got %q", m.View()[:min(40, len(m.View()))])
+	}
+
+	updated, _ = m.Update(osc52DoneMsg{m.osc52Gen})
+	m = updated.(Model)
+	if strings.Contains(m.View(), "\x1b]52") {
+		t.Error("Expected the sequence to leave the view once written")
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	// timestampMaskRegex matches ISO-ish dates with optional time, fractional
+	// seconds and zone, as well as bare clock times.
+	timestampMaskRegex = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?)?|\b\d{2}:\d{2}:\d{2}(?:[.,]\d+)?\b`)
+	numberMaskRegex    = regexp.MustCompile(`\d+`)
+
+	repeatBadgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+)
+
+// normalizeTemplate reduces a line to its "shape" so that lines differing only
+// in timestamps or numbers compare equal.
+func normalizeTemplate(line string) string {
+	line = stripAnsi(line)
+	line = timestampMaskRegex.ReplaceAllString(line, "<ts>")
+	line = numberMaskRegex.ReplaceAllString(line, "<n>")
+	return line
+}
+
+// collapseRepeats folds runs of consecutive near-identical lines into the first
+// line of the run. Its ref carries a "×N" badge with the first/last timestamps.
+func collapseRepeats(lines []string, refs []lineRef) ([]string, []lineRef) {
+	if len(lines) < 2 {
+		return lines, refs
+	}
+
+	collapsed := make([]string, 0, len(lines))
+	collapsedRefs := make([]lineRef, 0, len(refs))
+	runStart := 0
+	runKey := normalizeTemplate(lines[0])
+
+	flushRun := func(end int) {
+		ref := lineRef{first: refs[runStart].first, last: refs[end-1].last}
+		if count := end - runStart; count > 1 {
+			ref.repeat = repeatText(count, lines[runStart], lines[end-1])
+		}
+		collapsed = append(collapsed, lines[runStart])
+		collapsedRefs = append(collapsedRefs, ref)
+	}
+
+	for i := 1; i < len(lines); i++ {
+		key := normalizeTemplate(lines[i])
+		if key == runKey {
+			continue
+		}
+		flushRun(i)
+		runStart = i
+		runKey = key
+	}
+	flushRun(len(lines))
+
+	return collapsed, collapsedRefs
+}
+
+func repeatText(count int, first, last string) string {
+	badge := fmt.Sprintf("×%d", count)
+	firstTime, okFirst := extractDate(first)
+	lastTime, okLast := extractDate(last)
+	if okFirst && okLast {
+		badge += fmt.Sprintf(" [%s → %s]", formatRepeatTime(firstTime, lastTime), formatRepeatTime(lastTime, firstTime))
+	}
+	return badge
+}
+
+// repeatBadge renders the badge of a collapsed row, "" for other rows.
+func (m Model) repeatBadge(row int) string {
+	if row < 0 || row >= len(m.filteredRefs) || m.filteredRefs[row].repeat == "" {
+		return ""
+	}
+	return " " + repeatBadgeStyle.Render(m.filteredRefs[row].repeat)
+}
+
+// formatRepeatTime drops the date when both ends of a run fall on the same day.
+func formatRepeatTime(t, other time.Time) string {
+	if t.YearDay() == other.YearDay() && t.Year() == other.Year() {
+		return t.Format("15:04:05")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
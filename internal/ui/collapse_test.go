@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollapseRepeats(t *testing.T) {
+	lines := []string{
+		"2023-01-01 10:00:00 INFO health check ok (3ms)",
+		"2023-01-01 10:00:05 INFO health check ok (4ms)",
+		"2023-01-01 10:00:10 INFO health check ok (2ms)",
+		"2023-01-01 10:00:11 ERROR upstream timeout",
+		"2023-01-01 10:00:12 INFO health check ok (3ms)",
+	}
+
+	refs := make([]lineRef, len(lines))
+	for i := range refs {
+		refs[i] = lineRef{first: i, last: i}
+	}
+
+	got, gotRefs := collapseRepeats(lines, refs)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 rows, got %d: %q", len(got), got)
+	}
+
+	if got[0] != lines[0] {
+		t.Errorf("Collapsed row should be the first line of the run, got %q", got[0])
+	}
+	if gotRefs[0].repeat != "×3 [10:00:00 → 10:00:10]" {
+		t.Errorf("Expected ×3 badge with the first/last timestamps, got %q", gotRefs[0].repeat)
+	}
+
+	if got[1] != lines[3] || got[2] != lines[4] {
+		t.Errorf("Unique lines should pass through untouched, got %q", got[1:])
+	}
+
+	if gotRefs[0].first != 0 || gotRefs[0].last != 2 || gotRefs[1] != (lineRef{first: 3, last: 3}) {
+		t.Errorf("Collapsed rows should map back to their original lines, got %v", gotRefs)
+	}
+
+	// IDs are only masked for diffs; different requests stay apart.
+	ids := []string{"INFO done req=ab12cd34", "INFO done req=ef56ab78"}
+	if got, _ := collapseRepeats(ids, refs[:2]); len(got) != 2 {
+		t.Errorf("Expected lines with different IDs not to collapse, got %q", got)
+	}
+}
+
+func TestApplyFiltersCollapseDuplicates(t *testing.T) {
+	lines := []string{
+		"2023-01-01 10:00:00 WARN retrying connection attempt=1",
+		"2023-01-01 10:00:01 WARN retrying connection attempt=2",
+		"2023-01-01 10:00:02 INFO connected",
+	}
+
+	m := InitialModel("test.log", lines, nil)
+	m.collapseDuplicates = true
+	m.applyFilters(true)
+	if len(m.filteredLines) != 2 {
+		t.Fatalf("Expected 2 rows with collapsing, got %d", len(m.filteredLines))
+	}
+
+	// The badge is drawn, but copy, export and table parsing see the line.
+	if m.filteredLines[0] != lines[0] {
+		t.Errorf("Expected the row text to stay clean, got %q", m.filteredLines[0])
+	}
+	m = resize(m, 120, 10)
+	for _, wrap := range []bool{false, true} {
+		m.wrap = wrap
+		if body := stripAnsi(m.bodyView()); !strings.Contains(body, "attempt=1 ×2 [10:00:00 → 10:00:01]") {
+			t.Errorf("Expected the badge after the row (wrap %v), got %q", wrap, body)
+		}
+	}
+
+	// New lines must go through the collapse path rather than the fast append.
+	m.appendIncomingLines([]string{"2023-01-01 10:00:03 INFO connected"})
+	if len(m.filteredLines) != 2 {
+		t.Errorf("Expected appended repeat to join the last run, got %d rows", len(m.filteredLines))
+	}
+}
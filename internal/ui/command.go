@@ -0,0 +1,428 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+// command is one entry of the ":" command line.
+type command struct {
+	name  string
+	usage string
+}
+
+// commands lists every ":" command, for completion and the usage message.
+var commands = []command{
+	{"filter", "filter <text>"},
+	{"regex", "regex <pattern>"},
+	{"since", "since <time|15m>"},
+	{"until", "until <time>"},
+	{"diff", "diff <from>..<to> <from>..<to>"},
+	{"trace", "trace [id]"},
+	{"goto", "goto <line>"},
+	{"export", "export [file]"},
+	{"set", "set [no]<option>[!]"},
+	{"theme", "theme <name>"},
+	{"bookmark", "bookmark [note]"},
+	{"save", "save <name>"},
+	{"load", "load <name>"},
+	{"filters", "filters"},
+	{"delete", "delete <name>"},
+	{"hl", "hl <regex>"},
+	{"nohl", "nohl"},
+	{"tabnew", "tabnew [file]"},
+	{"tabclose", "tabclose"},
+	{"tabnext", "tabnext"},
+	{"tabprev", "tabprev"},
+	{"split", "split [file]"},
+	{"vsplit", "vsplit [file]"},
+	{"only", "only"},
+	{"sync", "sync"},
+	{"alerts", "alerts"},
+	{"restart", "restart"},
+	{"palette", "palette"},
+	{"help", "help"},
+	{"quit", "quit"},
+}
+
+// setOptions are the toggles reachable with :set.
+var setOptions = []string{"wrap", "fold", "collapse", "table", "follow", "regex", "redact", "detail", "number", "timesync"}
+
+// openCommandLine switches to the ":" prompt.
+func (m *Model) openCommandLine() tea.Cmd {
+	m.inputMode = ModeCommand
+	m.textInput.Placeholder = "filter, since, goto, set wrap, theme… (tab completes)"
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	m.resetHistoryBrowse()
+	return nil
+}
+
+// runCommand executes a line typed at the ":" prompt.
+func (m *Model) runCommand(line string) tea.Cmd {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	name, args, _ := strings.Cut(line, " ")
+	args = strings.TrimSpace(args)
+
+	// :N goes to line N and :N% to N percent of the file.
+	if n, err := strconv.Atoi(name); err == nil {
+		m.gotoLine(n)
+		return nil
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(name, "%")); err == nil {
+		m.gotoPercent(n)
+		return nil
+	}
+
+	switch name {
+	case "filter":
+		m.filterText = args
+		m.applyFilters(true)
+	case "regex":
+		m.regexMode = true
+		m.filterText = args
+		m.applyFilters(true)
+	case "since", "until":
+		m.setTimeBound(name, args)
+	case "diff":
+		if err := m.diffTimeRanges(args); err != nil {
+			m.statusMsg = "Cannot diff: " + err.Error()
+		}
+	case "trace":
+		m.followTrace(args)
+	case "goto":
+		n, err := strconv.Atoi(args)
+		if err != nil {
+			m.statusMsg = "Usage: :goto <line>"
+			return nil
+		}
+		m.gotoLine(n)
+	case "export":
+		if args == "" {
+			m.exportView()
+		} else {
+			m.exportTo(expandHome(args))
+		}
+	case "set":
+		m.setOption(args)
+	case "theme":
+		m.setTheme(args)
+	case "bookmark":
+		m.bookmarkCursor(args)
+	case "save":
+		m.saveFilter(args)
+	case "load":
+		m.loadFilter(args)
+	case "delete":
+		m.deleteFilter(args)
+	case "filters":
+		if names := m.savedFilterNames(); len(names) > 0 {
+			m.statusMsg = "Saved filters: " + strings.Join(names, ", ")
+		} else {
+			m.statusMsg = "No saved filters (:save <name>)"
+		}
+	case "hl":
+		if args == "" {
+			m.statusMsg = "Usage: :hl <regex>"
+			return nil
+		}
+		rule, err := newSessionHighlight(args, len(m.sessionHighlights))
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Invalid regex: %v", err)
+			return nil
+		}
+		m.sessionHighlights = append(m.sessionHighlights, rule)
+		m.layoutCache = make(map[int][]string)
+		m.statusMsg = fmt.Sprintf("Highlighting /%s/ (:nohl to clear)", args)
+	case "nohl":
+		m.sessionHighlights = nil
+		m.layoutCache = make(map[int][]string)
+		m.statusMsg = "Cleared session highlights"
+	case "tabnew":
+		cmd, err := m.newTab(args)
+		if err != nil {
+			m.statusMsg = "Cannot open tab: " + err.Error()
+		}
+		return cmd
+	case "tabclose":
+		m.closeTab()
+	case "tabnext", "tabprev":
+		step := 1
+		if name == "tabprev" {
+			step = -1
+		}
+		m.selectTab(m.activeTab + step)
+	case "split", "vsplit":
+		cmd, err := m.openSplit(args, name == "vsplit")
+		if err != nil {
+			m.statusMsg = "Cannot split: " + err.Error()
+		}
+		return cmd
+	case "only":
+		m.closeSplit()
+	case "sync":
+		m.setOption("timesync!")
+	case "alerts":
+		m.toggleAlerts()
+	case "restart":
+		return m.restartCommand()
+	case "palette":
+		return m.openPalette()
+	case "help":
+		m.showHelp = true
+	case "q", "quit":
+		return tea.Quit
+	default:
+		m.statusMsg = fmt.Sprintf("Unknown command: %s", name)
+	}
+	return nil
+}
+
+// setTimeBound handles :since and :until. An empty argument clears the bound;
+// a duration such as 15m counts back from the newest timestamp in the log.
+func (m *Model) setTimeBound(name, arg string) {
+	var bound *time.Time
+	if arg != "" {
+		var t time.Time
+		if d, err := time.ParseDuration(arg); err == nil {
+			t = m.latestTime().Add(-d)
+		} else if t, err = m.parseTargetTime(arg); err != nil {
+			m.statusMsg = fmt.Sprintf("Cannot parse time %q", arg)
+			return
+		}
+		bound = &t
+	}
+	if name == "since" {
+		m.startDate = bound
+	} else {
+		m.endDate = bound
+	}
+	m.applyFilters(true)
+}
+
+// latestTime is the newest timestamp in the log, or now.
+func (m *Model) latestTime() time.Time {
+	if t, ok := m.timeIndex().latest(); ok {
+		return t
+	}
+	return time.Now()
+}
+
+// setOption handles ":set wrap", ":set nowrap" and ":set wrap!".
+func (m *Model) setOption(arg string) {
+	name := strings.TrimSpace(arg)
+	toggle := strings.HasSuffix(name, "!")
+	name = strings.TrimSuffix(name, "!")
+	value := true
+	if !contains(setOptions, name) && strings.HasPrefix(name, "no") {
+		name, value = strings.TrimPrefix(name, "no"), false
+	}
+
+	var opt *bool
+	switch name {
+	case "wrap":
+		opt = &m.wrap
+	case "fold":
+		opt = &m.foldStackTraces
+	case "collapse":
+		opt = &m.collapseDuplicates
+	case "table":
+		opt = &m.tableMode
+	case "follow":
+		opt = &m.following
+	case "regex":
+		opt = &m.regexMode
+	case "redact":
+		opt = &m.redact
+	case "detail":
+		opt = &m.showDetail
+	case "number", "nu":
+		name, opt = "number", &m.lineNumbers
+	case "timesync":
+		if len(m.split) == 0 {
+			m.statusMsg = "Time sync needs a split (:split or :vsplit)"
+			return
+		}
+		opt = &m.timeSync
+	default:
+		m.statusMsg = fmt.Sprintf("Unknown option: %s", arg)
+		return
+	}
+	if toggle {
+		value = !*opt
+	}
+	*opt = value
+
+	switch name {
+	case "table":
+		if m.tableMode && len(m.tableColumns) == 0 {
+			m.tableColumns = detectColumns(m.filteredLines)
+		}
+		m.applyFilters(true)
+	case "fold", "collapse", "regex":
+		m.applyFilters(true)
+	case "redact":
+		m.layoutCache = make(map[int][]string)
+		m.applyFilters(false)
+	case "number":
+		m.layoutCache = make(map[int][]string)
+	case "follow":
+		m.setFollowing(value)
+	case "detail":
+		m.detailFocus = false
+		m.setCursor(m.cursor)
+	}
+	m.statusMsg = fmt.Sprintf("%s is %s", name, onOff(value))
+}
+
+// setTheme switches the color theme for this session.
+func (m *Model) setTheme(name string) {
+	cfg := m.cfg
+	cfg.Theme = name
+	if err := cfg.Validate(); err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+	m.cfg = cfg
+	applyTheme(cfg)
+	m.layoutCache = make(map[int][]string)
+	m.statusMsg = "Theme " + name
+}
+
+// bookmarkCursor bookmarks the cursor line, with an optional note shown in
+// the footer while the cursor is on it.
+func (m *Model) bookmarkCursor(note string) {
+	if len(m.filteredLines) == 0 {
+		return
+	}
+	m.bookmarks[m.cursor] = struct{}{}
+	if note != "" {
+		m.bookmarkNotes[m.cursor] = note
+	} else {
+		delete(m.bookmarkNotes, m.cursor)
+	}
+	delete(m.layoutCache, m.cursor)
+}
+
+// exportTo writes the filtered view to path.
+func (m *Model) exportTo(path string) {
+	var b strings.Builder
+	for _, line := range m.filteredLines {
+		b.WriteString(stripAnsi(line))
+		b.WriteByte('\n')
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			m.statusMsg = "Export failed: " + err.Error()
+			return
+		}
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		m.statusMsg = "Export failed: " + err.Error()
+		return
+	}
+	m.statusMsg = fmt.Sprintf("Exported %d lines to %s", len(m.filteredLines), path)
+}
+
+// completeCommand completes the command name or its argument. A unique match
+// is filled in; otherwise the common prefix is and the candidates are listed.
+func (m *Model) completeCommand() {
+	value := m.textInput.Value()
+	name, arg, hasArg := strings.Cut(value, " ")
+
+	var candidates []string
+	prefix := name
+	if !hasArg {
+		for _, c := range commands {
+			candidates = append(candidates, c.name)
+		}
+	} else {
+		prefix = strings.TrimLeft(arg, " ")
+		switch name {
+		case "set":
+			for _, o := range setOptions {
+				candidates = append(candidates, o, "no"+o)
+			}
+		case "theme":
+			candidates = append(candidates, config.BuiltinThemes...)
+			for t := range m.cfg.Themes {
+				candidates = append(candidates, t)
+			}
+			sort.Strings(candidates)
+		case "load", "delete":
+			candidates = m.savedFilterNames()
+		default:
+			return
+		}
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	completed := matches[0]
+	if len(matches) > 1 {
+		completed = commonPrefix(matches)
+		m.statusMsg = strings.Join(matches, "  ")
+	} else if !hasArg {
+		completed += " "
+	}
+	if hasArg {
+		completed = name + " " + completed
+	}
+	m.textInput.SetValue(completed)
+	m.textInput.CursorEnd()
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func onOff(v bool) string {
+	if v {
+		return "on"
+	}
+	return "off"
+}
+
+// expandHome replaces a leading ~ with the home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestMain(m *testing.M) {
+	// Keep prompt history written by tests out of the real state directory.
+	dir, err := os.MkdirTemp("", "lv-state")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_STATE_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func runCommandLine(m Model, line string) Model {
+	return pressKeys(m, ":", line, "enter")
+}
+
+func TestCommandFilterGotoAndSet(t *testing.T) {
+	lines := []string{"INFO a", "ERROR b", "INFO c", "ERROR d"}
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = runCommandLine(m, "filter error")
+	if len(m.filteredLines) != 2 {
+		t.Fatalf("Expected 2 lines after :filter, got %d", len(m.filteredLines))
+	}
+
+	m = runCommandLine(m, "goto 4")
+	if m.cursor != 1 {
+		t.Errorf("Expected :goto 4 to land on the second visible row, got %d", m.cursor)
+	}
+
+	m = runCommandLine(m, "set wrap")
+	if !m.wrap {
+		t.Error("Expected :set wrap to enable wrapping")
+	}
+	m = runCommandLine(m, "set wrap!")
+	if m.wrap {
+		t.Error("Expected :set wrap! to toggle wrapping off")
+	}
+	m = runCommandLine(m, "set nofold")
+	if m.foldStackTraces {
+		t.Error("Expected :set nofold to disable folding")
+	}
+
+	m = runCommandLine(m, "frobnicate")
+	if !strings.Contains(m.statusMsg, "Unknown command") {
+		t.Errorf("Expected an unknown command message, got %q", m.statusMsg)
+	}
+}
+
+func TestCommandSinceAndBookmarkNote(t *testing.T) {
+	lines := []string{
+		"2024-01-01 10:00:00 INFO a",
+		"2024-01-01 10:30:00 INFO b",
+		"2024-01-01 10:50:00 INFO c",
+	}
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = runCommandLine(m, "since 15m")
+	if len(m.filteredLines) != 1 || m.startDate == nil || !m.startDate.Equal(time.Date(2024, 1, 1, 10, 35, 0, 0, time.UTC)) {
+		t.Fatalf("Expected :since 15m to keep the last line, got %v", m.filteredLines)
+	}
+	m = runCommandLine(m, "since")
+	if m.startDate != nil || len(m.filteredLines) != 3 {
+		t.Error("Expected :since without argument to clear the bound")
+	}
+
+	m = runCommandLine(m, "bookmark check this")
+	if m.bookmarkNotes[0] != "check this" {
+		t.Errorf("Expected a bookmark note, got %v", m.bookmarkNotes)
+	}
+	if _, ok := m.bookmarks[0]; !ok {
+		t.Error("Expected :bookmark to bookmark the cursor line")
+	}
+}
+
+func TestCommandExportAndTheme(t *testing.T) {
+	t.Cleanup(func() { applyConfig(config.Default()) })
+	m := InitialModel("test.log", []string{"one", "two"}, nil)
+	path := filepath.Join(t.TempDir(), "out.log")
+
+	m = runCommandLine(m, "export "+path)
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "one\ntwo\n" {
+		t.Errorf("Expected the view to be exported, got %q (%v)", data, err)
+	}
+
+	m = runCommandLine(m, "theme nope")
+	if m.cfg.Theme == "nope" {
+		t.Error("Expected an unknown theme to be rejected")
+	}
+	m = runCommandLine(m, "theme light")
+	if m.cfg.Theme != "light" {
+		t.Errorf("Expected the theme to switch to light, got %q", m.cfg.Theme)
+	}
+}
+
+func TestCommandCompletionAndHistory(t *testing.T) {
+	m := InitialModel("test.log", []string{"a"}, nil)
+	m.history = loadHistory(filepath.Join(t.TempDir(), "history.json"))
+
+	m = pressKeys(m, ":", "got")
+	m = pressKeys(m, "tab")
+	if got := m.textInput.Value(); got != "goto " {
+		t.Errorf("Expected completion to goto, got %q", got)
+	}
+	m = pressKeys(m, "esc", ":", "set nowr", "tab")
+	if got := m.textInput.Value(); got != "set nowrap" {
+		t.Errorf("Expected option completion, got %q", got)
+	}
+	m = pressKeys(m, "esc")
+
+	m = runCommandLine(m, "set wrap")
+	m = runCommandLine(m, "set fold")
+	m = pressKeys(m, ":", "up")
+	if got := m.textInput.Value(); got != "set fold" {
+		t.Errorf("Expected the last command from history, got %q", got)
+	}
+	m = pressKeys(m, "up")
+	if got := m.textInput.Value(); got != "set wrap" {
+		t.Errorf("Expected the previous command from history, got %q", got)
+	}
+
+	reloaded := loadHistory(m.history.path)
+	if got := reloaded.list(promptCommand); len(got) != 2 {
+		t.Errorf("Expected history to be persisted, got %v", got)
+	}
+}
+
+func TestPaletteRunsAction(t *testing.T) {
+	m := InitialModel("test.log", []string{"a"}, nil)
+
+	if score, ok := fuzzyScore("tgwrp", "Toggle Wrap"); !ok || score == 0 {
+		t.Error("Expected a fuzzy subsequence match")
+	}
+	if _, ok := fuzzyScore("xyz", "Toggle Wrap"); ok {
+		t.Error("Expected no match")
+	}
+
+	for _, k := range []string{"ctrl+s", "enter", " ", "pgup", "G", "alt+x"} {
+		if got := keyMsgFromString(k).String(); got != k {
+			t.Errorf("keyMsgFromString(%q).String() = %q", k, got)
+		}
+	}
+
+	m.openPalette()
+	m = pressKeys(m, "toggle wrap", "enter")
+	if !m.wrap || m.inputMode != ModeNormal {
+		t.Errorf("Expected the palette to run Toggle Wrap, wrap=%v mode=%v", m.wrap, m.inputMode)
+	}
+}
+
+func TestPaletteWithNoMatches(t *testing.T) {
+	m := InitialModel("test.log", []string{"a"}, nil)
+	m.openPalette()
+	m = pressKeys(m, "zzzzqqq")
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.paletteCursor != 0 {
+		t.Errorf("Expected the cursor to stay at 0, got %d", m.paletteCursor)
+	}
+	m = pressKeys(m, "enter")
+	if m.inputMode != ModeNormal {
+		t.Errorf("Expected enter to close the palette without a match, mode=%v", m.inputMode)
+	}
+}
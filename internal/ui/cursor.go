@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"math"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// lineEnd is used as a selection column meaning "through the end of the line".
+const lineEnd = math.MaxInt32
+
+type visualMode int
+
+const (
+	visualNone visualMode = iota
+	visualChar            // v
+	visualLine            // V
+)
+
+var cursorLineStyle = lipgloss.NewStyle().Reverse(true)
+
+// gutter returns the prefix shown before a line in no-wrap mode: the line
+// number when enabled, then 3 cells for the cursor and bookmark marks.
+func (m Model) gutter(row int) string {
+	_, bookmarked := m.bookmarks[row]
+	mark := "   "
+	switch {
+	case row == m.cursor && bookmarked:
+		mark = "▶🔖"
+	case row == m.cursor:
+		mark = "▶  "
+	case bookmarked:
+		mark = "🔖 "
+	}
+	return m.lineNumber(row) + mark
+}
+
+// setCursor moves the cursor line, scrolling the list to keep it visible and
+// resetting the detail pane for the new record.
+func (m *Model) setCursor(row int) {
+	row = max(0, min(row, len(m.filteredLines)-1))
+	if row != m.cursor {
+		m.detailCursor = 0
+		m.detailScroll = 0
+		m.detailFolds = make(map[string]bool)
+	}
+	m.cursor = row
+
+	if m.cursor < m.yOffset {
+		m.yOffset = m.cursor
+	}
+	if h := m.pageHeight(); h > 0 && m.cursor >= m.yOffset+h {
+		m.yOffset = m.cursor - h + 1
+	}
+}
+
+// cursorRunes returns the plain text of the cursor line.
+func (m Model) cursorRunes() []rune {
+	if m.cursor < 0 || m.cursor >= len(m.filteredLines) {
+		return nil
+	}
+	return []rune(stripAnsi(m.filteredLines[m.cursor]))
+}
+
+// startVisual enters visual mode anchored at the cursor.
+func (m *Model) startVisual(mode visualMode) {
+	m.visualMode = mode
+	m.visualAnchor = Point{X: m.cursorX, Y: m.cursor}
+	m.updateVisualSelection()
+}
+
+func (m *Model) exitVisual() {
+	m.visualMode = visualNone
+	m.selectionStart = nil
+	m.selectionEnd = nil
+}
+
+// updateVisualSelection mirrors the anchor and cursor into the selection
+// points shared with mouse selection, so View and copySelection need no
+// special casing.
+func (m *Model) updateVisualSelection() {
+	start := m.visualAnchor
+	end := Point{X: m.cursorX, Y: m.cursor}
+
+	if m.visualMode == visualLine {
+		if start.Y > end.Y {
+			start, end = end, start
+		}
+		start.X = 0
+		end.X = lineEnd
+	}
+	m.selectionStart = &start
+	m.selectionEnd = &end
+}
+
+// handleVisualKey applies vim-style motions while in visual mode.
+func (m *Model) handleVisualKey(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, m.keys.VisualDown):
+		m.setCursor(m.cursor + 1)
+	case key.Matches(msg, m.keys.VisualUp):
+		m.setCursor(m.cursor - 1)
+	case key.Matches(msg, m.keys.VisualRight):
+		m.cursorX++
+	case key.Matches(msg, m.keys.VisualLeft):
+		m.cursorX--
+	case key.Matches(msg, m.keys.VisualWordNext):
+		m.wordForward()
+	case key.Matches(msg, m.keys.VisualWordPrev):
+		m.wordBackward()
+	case key.Matches(msg, m.keys.VisualLineStart):
+		m.cursorX = 0
+	case key.Matches(msg, m.keys.VisualLineEnd):
+		m.cursorX = max(0, len(m.cursorRunes())-1)
+	case key.Matches(msg, m.keys.VisualTop):
+		m.setCursor(0)
+	case key.Matches(msg, m.keys.VisualBottom):
+		m.setCursor(len(m.filteredLines) - 1)
+	case key.Matches(msg, m.keys.VisualChars, m.keys.VisualLines):
+		mode := visualChar
+		if key.Matches(msg, m.keys.VisualLines) {
+			mode = visualLine
+		}
+		if mode == m.visualMode {
+			m.exitVisual()
+			return true
+		}
+		m.visualMode = mode
+	case key.Matches(msg, m.keys.VisualCopy):
+		m.copySelection()
+		m.exitVisual()
+		return true
+	case key.Matches(msg, m.keys.VisualCancel):
+		m.exitVisual()
+		return true
+	default:
+		return false
+	}
+
+	m.cursorX = max(0, min(m.cursorX, len(m.cursorRunes())-1))
+	m.scrollToCursorX()
+	m.updateVisualSelection()
+	return true
+}
+
+// scrollToCursorX keeps the cursor column visible in no-wrap mode.
+func (m *Model) scrollToCursorX() {
+	if m.wrap {
+		return
+	}
+	width := m.screenWidth - m.gutterWidth()
+	if m.cursorX < m.xOffset {
+		m.xOffset = m.cursorX
+	} else if width > 0 && m.cursorX >= m.xOffset+width {
+		m.xOffset = m.cursorX - width + 1
+	}
+}
+
+// wordForward moves to the start of the next whitespace separated word,
+// continuing onto following lines.
+func (m *Model) wordForward() {
+	runes := m.cursorRunes()
+	i := m.cursorX
+	for i < len(runes) && !unicode.IsSpace(runes[i]) {
+		i++
+	}
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	if i < len(runes) {
+		m.cursorX = i
+		return
+	}
+	if m.cursor < len(m.filteredLines)-1 {
+		m.setCursor(m.cursor + 1)
+		runes = m.cursorRunes()
+		i = 0
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+		m.cursorX = i
+	}
+}
+
+// wordBackward moves to the start of the previous word, continuing onto
+// preceding lines.
+func (m *Model) wordBackward() {
+	runes := m.cursorRunes()
+	i := min(m.cursorX, len(runes)) - 1
+	for i >= 0 && unicode.IsSpace(runes[i]) {
+		i--
+	}
+	if i < 0 {
+		if m.cursor == 0 {
+			m.cursorX = 0
+			return
+		}
+		m.setCursor(m.cursor - 1)
+		runes = m.cursorRunes()
+		i = len(runes) - 1
+		for i >= 0 && unicode.IsSpace(runes[i]) {
+			i--
+		}
+	}
+	for i > 0 && !unicode.IsSpace(runes[i-1]) {
+		i--
+	}
+	m.cursorX = max(0, i)
+}
+
+// copyCursorLine yanks the whole cursor line.
+func (m *Model) copyCursorLine() {
+	m.selectionStart = &Point{X: 0, Y: m.cursor}
+	m.selectionEnd = &Point{X: lineEnd, Y: m.cursor}
+	m.copySelection()
+}
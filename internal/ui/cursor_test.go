@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func pressKeys(m Model, keys ...string) Model {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestVisualCharSelection(t *testing.T) {
+	lines := []string{
+		"alpha beta gamma",
+		"delta epsilon",
+	}
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = pressKeys(m, "v", "w", "w")
+	if m.selectionStart == nil || m.selectionEnd == nil {
+		t.Fatal("Expected a selection in visual mode")
+	}
+	if *m.selectionStart != (Point{0, 0}) || *m.selectionEnd != (Point{11, 0}) {
+		t.Errorf("Unexpected selection %v..%v", *m.selectionStart, *m.selectionEnd)
+	}
+
+	// w at the last word wraps onto the next line.
+	m = pressKeys(m, "w")
+	if m.cursor != 1 || m.cursorX != 0 {
+		t.Errorf("Expected cursor at start of line 1, got %d:%d", m.cursor, m.cursorX)
+	}
+
+	m = pressKeys(m, "$")
+	if m.cursorX != len("delta epsilon")-1 {
+		t.Errorf("Expected $ to move to end of line, got %d", m.cursorX)
+	}
+
+	m = pressKeys(m, "b", "b")
+	if m.cursor != 1 || m.cursorX != 0 {
+		t.Errorf("Expected b to move back to 'delta', got %d:%d", m.cursor, m.cursorX)
+	}
+
+	m = pressKeys(m, "esc")
+	if m.visualMode != visualNone || m.selectionStart != nil {
+		t.Error("Expected esc to leave visual mode and clear the selection")
+	}
+}
+
+func TestVisualLineSelection(t *testing.T) {
+	lines := []string{"one", "two", "three"}
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = pressKeys(m, "j", "V", "j")
+	if m.selectionStart.Y != 1 || m.selectionEnd.Y != 2 {
+		t.Errorf("Expected lines 1-2 selected, got %d-%d", m.selectionStart.Y, m.selectionEnd.Y)
+	}
+	if m.selectionStart.X != 0 || m.selectionEnd.X != lineEnd {
+		t.Error("Expected linewise selection to span whole lines")
+	}
+
+	// Moving above the anchor flips the range.
+	m = pressKeys(m, "k", "k")
+	if m.selectionStart.Y != 0 || m.selectionEnd.Y != 1 {
+		t.Errorf("Expected lines 0-1 selected, got %d-%d", m.selectionStart.Y, m.selectionEnd.Y)
+	}
+}
+
+func TestBookmarkTargetsCursor(t *testing.T) {
+	lines := []string{"a", "b", "c", "d"}
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = pressKeys(m, "j", "j", "m")
+	if _, ok := m.bookmarks[2]; !ok {
+		t.Fatalf("Expected bookmark on cursor line 2, got %v", m.bookmarks)
+	}
+
+	m = pressKeys(m, "g", "n")
+	if m.cursor != 2 {
+		t.Errorf("Expected n to move the cursor to the bookmark, got %d", m.cursor)
+	}
+}
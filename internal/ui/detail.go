@@ -0,0 +1,333 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxRecordContinuation = 500
+
+type detailNodeKind int
+
+const (
+	detailScalar detailNodeKind = iota
+	detailObject
+	detailArray
+)
+
+// detailNode is one value of a JSON document, kept in source key order.
+type detailNode struct {
+	key      string
+	kind     detailNodeKind
+	value    string // Scalar rendering (strings quoted)
+	parsed   bool   // String field that held embedded JSON
+	children []*detailNode
+}
+
+// detailRow is one visible line of the detail pane.
+type detailRow struct {
+	path  string // Fold key for containers
+	depth int
+	node  *detailNode // nil for plain text rows
+	text  string      // Plain text rows only
+	copy  string      // Value yanked with y
+}
+
+var (
+	detailBorderStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	detailCursorStyle   = lipgloss.NewStyle().Reverse(true)
+	detailParsedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+	detailCollapsedHint = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+// parseDetailJSON parses a JSON object or array, preserving key order. Lines
+// with a plain-text prefix (e.g. "2023-01-01 INFO {...}") are accepted too.
+func parseDetailJSON(s string) (*detailNode, bool) {
+	s = strings.TrimSpace(stripAnsi(s))
+	if s == "" {
+		return nil, false
+	}
+	if s[0] != '{' && s[0] != '[' {
+		idx := strings.IndexAny(s, "{[")
+		if idx < 0 {
+			return nil, false
+		}
+		s = s[idx:]
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	node, err := decodeDetailValue(dec, "")
+	if err != nil || node.kind == detailScalar || dec.More() {
+		return nil, false
+	}
+	return node, true
+}
+
+func decodeDetailValue(dec *json.Decoder, key string) (*detailNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &detailNode{key: key}
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			node.kind = detailObject
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				k, _ := kt.(string)
+				child, err := decodeDetailValue(dec, k)
+				if err != nil {
+					return nil, err
+				}
+				node.children = append(node.children, child)
+			}
+		} else {
+			node.kind = detailArray
+			for i := 0; dec.More(); i++ {
+				child, err := decodeDetailValue(dec, fmt.Sprintf("[%d]", i))
+				if err != nil {
+					return nil, err
+				}
+				node.children = append(node.children, child)
+			}
+		}
+		// Closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		// Expand JSON-in-string fields (e.g. "payload": "{\"a\":1}").
+		if t := strings.TrimSpace(v); strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[") {
+			if inner, ok := parseDetailJSON(t); ok {
+				inner.key = key
+				inner.parsed = true
+				return inner, nil
+			}
+		}
+		node.value = strconv.Quote(v)
+	case json.Number:
+		node.value = v.String()
+	case bool:
+		node.value = strconv.FormatBool(v)
+	case nil:
+		node.value = "null"
+	}
+	return node, nil
+}
+
+// compact renders the node back to single-line JSON in source key order.
+func (n *detailNode) compact() string {
+	switch n.kind {
+	case detailObject:
+		parts := make([]string, len(n.children))
+		for i, c := range n.children {
+			parts[i] = strconv.Quote(c.key) + ":" + c.compact()
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	case detailArray:
+		parts := make([]string, len(n.children))
+		for i, c := range n.children {
+			parts[i] = c.compact()
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	}
+	return n.value
+}
+
+// copyValue is what gets yanked for a node: unquoted strings, compact JSON otherwise.
+func (n *detailNode) copyValue() string {
+	if n.kind == detailScalar {
+		if s, err := strconv.Unquote(n.value); err == nil {
+			return s
+		}
+	}
+	return n.compact()
+}
+
+// flattenDetail lists the visible rows of a JSON tree, skipping the children
+// of folded containers. The root itself is not shown.
+func flattenDetail(root *detailNode, folds map[string]bool) []detailRow {
+	var rows []detailRow
+	var walk func(n *detailNode, path string, depth int)
+	walk = func(n *detailNode, path string, depth int) {
+		for _, c := range n.children {
+			childPath := path + "/" + c.key
+			rows = append(rows, detailRow{path: childPath, depth: depth, node: c, copy: c.copyValue()})
+			if c.kind != detailScalar && !folds[childPath] {
+				walk(c, childPath, depth+1)
+			}
+		}
+	}
+	walk(root, "", 0)
+	return rows
+}
+
+func renderDetailRow(r detailRow, folds map[string]bool) string {
+	if r.node == nil {
+		return r.text
+	}
+	indent := strings.Repeat("  ", r.depth)
+	key := jsonKeyStyle.Render(r.node.key)
+
+	switch r.node.kind {
+	case detailObject, detailArray:
+		opening, closing := "{", "}"
+		if r.node.kind == detailArray {
+			opening, closing = "[", "]"
+		}
+		var s string
+		if folds[r.path] {
+			s = fmt.Sprintf("%s▸ %s: %s", indent, key, detailCollapsedHint.Render(fmt.Sprintf("%s…%d%s", opening, len(r.node.children), closing)))
+		} else {
+			s = fmt.Sprintf("%s▾ %s: %s%d%s", indent, key, opening, len(r.node.children), closing)
+		}
+		if r.node.parsed {
+			s += " " + detailParsedStyle.Render("(parsed from string)")
+		}
+		return s
+	}
+	return fmt.Sprintf("%s  %s: %s", indent, key, highlightLine(r.node.value))
+}
+
+// recordLines returns the original lines behind a displayed row, extended with
+// any indented continuation lines (stack traces) that follow it.
+func (m Model) recordLines(row int) []string {
+	if row < 0 || row >= len(m.filteredRefs) {
+		return nil
+	}
+	ref := m.filteredRefs[row]
+	if ref.first < 0 || ref.last >= len(m.originalLines) {
+		return []string{m.filteredLines[row]} // Already redacted
+	}
+
+	lines := append([]string(nil), m.originalLines[ref.first:ref.last+1]...)
+	for i := ref.last + 1; i < len(m.originalLines) && i-ref.last <= maxRecordContinuation; i++ {
+		next := m.originalLines[i]
+		if !strings.HasPrefix(next, "\t") && !strings.HasPrefix(next, "  ") {
+			break
+		}
+		lines = append(lines, next)
+	}
+	if m.redact {
+		for i, line := range lines {
+			lines[i] = redactLine(line)
+		}
+	}
+	return lines
+}
+
+// detailRows builds the rows of the detail pane for the cursor line: a JSON
+// tree when the record parses, otherwise the wrapped plain-text record.
+func (m Model) detailRows() []detailRow {
+	record := m.recordLines(m.cursor)
+	if len(record) == 0 {
+		return nil
+	}
+
+	if root, ok := parseDetailJSON(record[0]); ok && len(record) == 1 {
+		return flattenDetail(root, m.detailFolds)
+	}
+
+	width := m.screenWidth
+	if width <= 0 {
+		width = 80
+	}
+	var rows []detailRow
+	for _, line := range record {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		wrapped := lipgloss.NewStyle().Width(width).Render(line)
+		for _, part := range strings.Split(wrapped, "\n") {
+			rows = append(rows, detailRow{text: highlightLine(part), copy: line})
+		}
+	}
+	return rows
+}
+
+// detailHeight is the number of screen rows given to the detail pane,
+// including its title bar.
+func (m Model) detailHeight() int {
+	if !m.showDetail {
+		return 0
+	}
+	return max(3, m.viewport.Height/2)
+}
+
+func (m Model) detailView() string {
+	rows := m.detailRows()
+	height := m.detailHeight() - 1
+
+	focus := firstKey(m.keys.DetailFocus) + ": focus"
+	if m.detailFocus {
+		focus = fmt.Sprintf("%s/%s: move  %s: fold  %s: copy  %s: back",
+			firstKey(m.keys.DetailDown), firstKey(m.keys.DetailUp), firstKey(m.keys.DetailFold),
+			firstKey(m.keys.DetailCopy), firstKey(m.keys.DetailFocus))
+	}
+	title := fmt.Sprintf("── Detail (%d/%d) ── %s ", min(m.detailCursor+1, len(rows)), len(rows), focus)
+	title += strings.Repeat("─", max(0, m.screenWidth-lipgloss.Width(title)))
+
+	out := make([]string, 0, height+1)
+	out = append(out, detailBorderStyle.Render(title))
+	for i := m.detailScroll; i < len(rows) && len(out) <= height; i++ {
+		line := renderDetailRow(rows[i], m.detailFolds)
+		if m.detailFocus && i == m.detailCursor {
+			line = detailCursorStyle.Render(stripAnsi(line))
+		}
+		out = append(out, line)
+	}
+	for len(out) <= height {
+		out = append(out, "")
+	}
+	return strings.Join(out, "\n")
+}
+
+// handleDetailKey handles keys while the detail pane has focus.
+func (m *Model) handleDetailKey(msg tea.KeyMsg) bool {
+	rows := m.detailRows()
+	switch {
+	case key.Matches(msg, m.keys.DetailUp):
+		m.detailCursor--
+	case key.Matches(msg, m.keys.DetailDown):
+		m.detailCursor++
+	case key.Matches(msg, m.keys.DetailTop):
+		m.detailCursor = 0
+	case key.Matches(msg, m.keys.DetailBottom):
+		m.detailCursor = len(rows) - 1
+	case key.Matches(msg, m.keys.DetailFold):
+		if m.detailCursor < len(rows) {
+			r := rows[m.detailCursor]
+			if r.node != nil && r.node.kind != detailScalar {
+				m.detailFolds[r.path] = !m.detailFolds[r.path]
+			}
+		}
+	case key.Matches(msg, m.keys.DetailCopy):
+		if m.detailCursor < len(rows) {
+			m.copyToClipboard(rows[m.detailCursor].copy)
+		}
+	case key.Matches(msg, m.keys.DetailClose):
+		m.detailFocus = false
+	default:
+		return false
+	}
+
+	m.detailCursor = max(0, min(m.detailCursor, len(m.detailRows())-1))
+	height := m.detailHeight() - 1
+	if m.detailCursor < m.detailScroll {
+		m.detailScroll = m.detailCursor
+	}
+	if m.detailCursor >= m.detailScroll+height {
+		m.detailScroll = m.detailCursor - height + 1
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDetailJSON(t *testing.T) {
+	line := `2023-01-01 INFO {"b":1,"a":{"x":"y"},"payload":"{\"inner\":[1,2]}"}`
+	root, ok := parseDetailJSON(line)
+	if !ok {
+		t.Fatal("Expected prefixed JSON to parse")
+	}
+
+	rows := flattenDetail(root, map[string]bool{})
+	var paths []string
+	for _, r := range rows {
+		paths = append(paths, r.path)
+	}
+	want := []string{"/b", "/a", "/a/x", "/payload", "/payload/inner", "/payload/inner/[0]", "/payload/inner/[1]"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Rows = %v, want %v", paths, want)
+	}
+	if !rows[3].node.parsed {
+		t.Error("Expected JSON-in-string field to be marked as parsed")
+	}
+	if rows[1].copy != `{"x":"y"}` || rows[2].copy != "y" {
+		t.Errorf("Unexpected copy values %q, %q", rows[1].copy, rows[2].copy)
+	}
+
+	folded := flattenDetail(root, map[string]bool{"/a": true, "/payload": true})
+	if len(folded) != 3 {
+		t.Errorf("Expected folded containers to hide their children, got %d rows", len(folded))
+	}
+
+	if _, ok := parseDetailJSON("plain text line"); ok {
+		t.Error("Plain text should not parse as JSON")
+	}
+}
+
+func TestRecordLinesExpandsFoldedTrace(t *testing.T) {
+	lines := []string{
+		"2023-01-01 10:00:00 ERROR boom",
+		"    at foo()",
+		"    at bar()",
+		"2023-01-01 10:00:01 INFO next",
+	}
+	m := InitialModel("test.log", lines, nil)
+	m.foldStackTraces = true
+	m.applyFilters(true)
+
+	// Row 0 is the error line, its trace follows as a continuation.
+	if got := m.recordLines(0); len(got) != 3 {
+		t.Errorf("Expected error record with its trace, got %q", got)
+	}
+	// Row 1 is the fold summary, which expands to the folded lines.
+	if got := m.recordLines(1); !reflect.DeepEqual(got, lines[1:3]) {
+		t.Errorf("Expected fold summary to expand to the trace, got %q", got)
+	}
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A diff compares two sets of lines by message template (see
+// diffTemplate) rather than by text: what matters after a deploy is
+// which kinds of message are new, gone, or much more or less frequent.
+
+const (
+	// diffRatio is how much a template's share of the lines must grow or
+	// shrink to be reported as changed.
+	diffRatio = 2.0
+	// diffMinCount keeps rare templates (1 → 2) from being reported as changed.
+	diffMinCount = 3
+)
+
+// idMaskRegex matches UUIDs and hex runs; only those mixing digits and
+// letters are masked, so words like "added" and plain numbers are kept.
+var idMaskRegex = regexp.MustCompile(`\b[0-9a-fA-F]{8}(?:-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}\b|\b(?:0x)?[0-9a-fA-F]{6,}\b`)
+
+// diffTemplate is normalizeTemplate with request and trace IDs masked too,
+// since two logs never share those.
+func diffTemplate(line string) string {
+	line = timestampMaskRegex.ReplaceAllString(stripAnsi(line), "<ts>")
+	line = idMaskRegex.ReplaceAllStringFunc(line, func(id string) string {
+		if strings.ContainsAny(id, "0123456789") && strings.ContainsAny(strings.ToLower(id), "abcdefx") {
+			return "<id>"
+		}
+		return id
+	})
+	return normalizeTemplate(line)
+}
+
+// templateDiff is one reported template with its count on each side.
+type templateDiff struct {
+	template string
+	a, b     int
+}
+
+// ratio is the change in the template's share of the lines from a to b.
+// It is 0 for new and gone templates.
+func (d templateDiff) ratio(totalA, totalB int) float64 {
+	if d.a == 0 || d.b == 0 {
+		return 0
+	}
+	return (float64(d.b) / float64(totalB)) / (float64(d.a) / float64(totalA))
+}
+
+// countTemplates counts the non-blank lines of each template.
+func countTemplates(lines []string) (map[string]int, int) {
+	counts := make(map[string]int)
+	total := 0
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		counts[strings.TrimSpace(diffTemplate(l))]++
+		total++
+	}
+	return counts, total
+}
+
+// diffTemplates returns the templates that are new in b, gone from b, or
+// whose frequency changed by diffRatio or more: new ones first, then the
+// biggest changes, then gone ones.
+func diffTemplates(a, b []string) (diffs []templateDiff, totalA, totalB int) {
+	countsA, totalA := countTemplates(a)
+	countsB, totalB := countTemplates(b)
+
+	for t, nb := range countsB {
+		na := countsA[t]
+		d := templateDiff{t, na, nb}
+		if na == 0 {
+			diffs = append(diffs, d)
+		} else if r := d.ratio(totalA, totalB); max(na, nb) >= diffMinCount && (r >= diffRatio || r <= 1/diffRatio) {
+			diffs = append(diffs, d)
+		}
+	}
+	for t, na := range countsA {
+		if countsB[t] == 0 {
+			diffs = append(diffs, templateDiff{t, na, 0})
+		}
+	}
+
+	rank := func(d templateDiff) int {
+		switch {
+		case d.a == 0:
+			return 0
+		case d.b == 0:
+			return 2
+		}
+		return 1
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		di, dj := diffs[i], diffs[j]
+		if ri, rj := rank(di), rank(dj); ri != rj {
+			return ri < rj
+		}
+		if rank(di) == 1 {
+			// Largest change either way first
+			ci, cj := di.ratio(totalA, totalB), dj.ratio(totalA, totalB)
+			ci, cj = math.Max(ci, 1/ci), math.Max(cj, 1/cj)
+			if ci != cj {
+				return ci > cj
+			}
+		}
+		if ni, nj := di.a+di.b, dj.a+dj.b; ni != nj {
+			return ni > nj
+		}
+		return di.template < dj.template
+	})
+	return diffs, totalA, totalB
+}
+
+// DiffReport compares lines b against lines a by template and returns the
+// report as lines: a summary, then one line per new (+), changed (~) or
+// gone (-) template with its counts.
+func DiffReport(nameA string, a []string, nameB string, b []string) []string {
+	diffs, totalA, totalB := diffTemplates(a, b)
+	var added, changed, gone int
+	for _, d := range diffs {
+		switch {
+		case d.a == 0:
+			added++
+		case d.b == 0:
+			gone++
+		default:
+			changed++
+		}
+	}
+
+	report := []string{
+		fmt.Sprintf("A: %s (%d lines)", nameA, totalA),
+		fmt.Sprintf("B: %s (%d lines)", nameB, totalB),
+		fmt.Sprintf("%d new, %d changed, %d gone templates", added, changed, gone),
+	}
+	for _, d := range diffs {
+		var mark, change string
+		switch {
+		case d.a == 0:
+			mark, change = "+", "new"
+		case d.b == 0:
+			mark, change = "-", "gone"
+		default:
+			mark, change = "~", fmt.Sprintf("×%.1f", d.ratio(totalA, totalB))
+		}
+		report = append(report, fmt.Sprintf("%s %-6s %7d → %-7d %s", mark, change, d.a, d.b, d.template))
+	}
+	return report
+}
+
+// diffTimeRanges handles :diff <from>..<to> <from>..<to>, comparing two time
+// windows of the file. The report opens in a new tab.
+func (m *Model) diffTimeRanges(args string) error {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		return fmt.Errorf("expected two ranges such as 10:00..10:30 11:00..11:30")
+	}
+	var windows [2][]string
+	var names [2]string
+	for i, f := range fields {
+		from, to, ok := strings.Cut(f, "..")
+		if !ok {
+			return fmt.Errorf("%q is not a range (from..to)", f)
+		}
+		start, err := m.parseTargetTime(from)
+		if err != nil {
+			return fmt.Errorf("cannot parse time %q", from)
+		}
+		end, err := m.parseTargetTime(to)
+		if err != nil {
+			return fmt.Errorf("cannot parse time %q", to)
+		}
+		windows[i] = m.linesBetween(start, end)
+		names[i] = fmt.Sprintf("%s %s", filepath.Base(m.filename), f)
+	}
+
+	report := DiffReport(names[0], windows[0], names[1], windows[1])
+	tab := newModelWithStore(&lineStore{name: "diff " + args, lines: report, generated: true}, m.cfg)
+	tab.history = m.history
+	m.insertTab(tab)
+	return nil
+}
+
+// linesBetween returns the timed lines of the file from start up to end, with
+// their continuation lines.
+func (m *Model) linesBetween(start, end time.Time) []string {
+	ix := m.timeIndex()
+	lo, hi := ix.span(&start, &end)
+	var lines []string
+	for i := lo; i < hi; i++ {
+		if _, ok := ix.at(i); ok && ix.inRange(i, &start, &end) {
+			lines = append(lines, m.store.lines[i])
+		}
+	}
+	return lines
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiffTemplates(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 10; i++ {
+		a = append(a, fmt.Sprintf("2024-01-01 10:00:%02d INFO request id=%x took %dms", i, 0xab12cd00+i, i))
+		b = append(b, fmt.Sprintf("2024-01-02 10:00:%02d INFO request id=%x took %dms", i, 0x5512cd00+i, i*7))
+	}
+	a = append(a, "2024-01-01 10:01:00 WARN cache miss key=7")
+	for i := 0; i < 4; i++ {
+		a = append(a, "2024-01-01 10:02:00 INFO retry 1")
+		b = append(b, "2024-01-02 10:02:00 INFO retry 1", "2024-01-02 10:02:00 INFO retry 2", "2024-01-02 10:02:00 INFO retry 3", "2024-01-02 10:02:00 INFO retry 4")
+	}
+	b = append(b, "2024-01-02 10:03:00 ERROR db timeout trace=6f1c2e0a-9b7d-4c21-8e3f-0123456789ab")
+
+	diffs, totalA, totalB := diffTemplates(a, b)
+	if totalA != 15 || totalB != 27 {
+		t.Fatalf("Expected 15 and 27 counted lines, got %d and %d", totalA, totalB)
+	}
+	var got []string
+	for _, d := range diffs {
+		got = append(got, fmt.Sprintf("%d→%d %s", d.a, d.b, d.template))
+	}
+	want := []string{
+		"0→1 <ts> ERROR db timeout trace=<id>",
+		"4→16 <ts> INFO retry <n>",
+		"1→0 <ts> WARN cache miss key=<n>",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected new, changed and gone templates in order, got:\n%s", strings.Join(got, "\n"))
+	}
+
+	report := DiffReport("a.log", a, "b.log", b)
+	if report[2] != "1 new, 1 changed, 1 gone templates" || !strings.HasPrefix(report[4], "~ ×2.2") {
+		t.Errorf("Unexpected report:\n%s", strings.Join(report, "\n"))
+	}
+}
+
+func TestDiffTimeRanges(t *testing.T) {
+	lines := []string{
+		"2024-01-01 10:00:00 INFO started",
+		"2024-01-01 10:05:00 INFO healthy",
+		"2024-01-01 11:00:00 INFO healthy",
+		"2024-01-01 11:05:00 ERROR out of memory",
+		"  at alloc.go:12",
+	}
+	m := InitialModel("app.log", lines, nil)
+	m = runCommandLine(m, "diff 10:00..10:30 11:00..11:30")
+	if m.tabCount() != 2 || m.activeTab != 1 {
+		t.Fatalf("Expected the report in a new tab, got %d tabs (%s)", m.tabCount(), m.statusMsg)
+	}
+	report := strings.Join(m.filteredLines, "\n")
+	for _, want := range []string{"+ new", "ERROR out of memory", "at alloc.go:<n>", "- gone", "INFO started"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in the report:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "healthy") {
+		t.Error("Expected the unchanged template to be left out")
+	}
+
+	m = runCommandLine(m, "diff 10:00")
+	if !strings.HasPrefix(m.statusMsg, "Cannot diff") {
+		t.Errorf("Expected an error for a missing range, got %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Field is a single key/value pair extracted from a structured log record.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Record is an ordered set of fields parsed from a JSON or logfmt line.
+type Record []Field
+
+// Get returns the value of the first field named key.
+func (r Record) Get(key string) (string, bool) {
+	for _, f := range r {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// parseRecord extracts fields from a JSON object or logfmt line, keeping the
+// order in which keys appear. ok is false for unstructured lines.
+func parseRecord(line string) (Record, bool) {
+	trimmed := strings.TrimSpace(stripAnsi(line))
+	isJSON := strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}")
+
+	switch recordFormat {
+	case "text":
+		return nil, false
+	case "json":
+		if !isJSON {
+			return nil, false
+		}
+	case "logfmt":
+		return parseLogfmtRecord(trimmed)
+	}
+
+	if isJSON {
+		return parseJSONRecord(trimmed)
+	}
+	return parseLogfmtRecord(trimmed)
+}
+
+func parseJSONRecord(s string) (Record, bool) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+
+	var rec Record
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, false
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, false
+		}
+		rec = append(rec, Field{Key: key, Value: jsonValueString(raw)})
+	}
+	return rec, true
+}
+
+// jsonValueString renders strings unquoted and everything else as compact JSON.
+func jsonValueString(raw json.RawMessage) string {
+	var str string
+	if json.Unmarshal(raw, &str) == nil {
+		return str
+	}
+	var buf bytes.Buffer
+	if json.Compact(&buf, raw) == nil {
+		return buf.String()
+	}
+	return string(raw)
+}
+
+// parseLogfmtRecord parses key=value pairs separated by spaces. Values may be
+// double quoted. Lines with fewer than two pairs are not treated as logfmt.
+func parseLogfmtRecord(s string) (Record, bool) {
+	var rec Record
+	i := 0
+	for i < len(s) {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+
+		keyStart := i
+		for i < len(s) && s[i] != '=' && s[i] != ' ' {
+			i++
+		}
+		if i >= len(s) || s[i] != '=' || i == keyStart {
+			// Bare word: skip it, it is not part of a pair.
+			for i < len(s) && s[i] != ' ' {
+				i++
+			}
+			continue
+		}
+		key := s[keyStart:i]
+		i++ // skip '='
+
+		var value string
+		if i < len(s) && s[i] == '"' {
+			i++
+			var b strings.Builder
+			for i < len(s) && s[i] != '"' {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			i++ // closing quote
+			value = b.String()
+		} else {
+			valStart := i
+			for i < len(s) && s[i] != ' ' {
+				i++
+			}
+			value = s[valStart:i]
+		}
+		rec = append(rec, Field{Key: key, Value: value})
+	}
+
+	if len(rec) < 2 {
+		return nil, false
+	}
+	return rec, true
+}
@@ -0,0 +1,408 @@
+package ui
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+// globTailBytes bounds how much of each matching file is read when a glob
+// opens: a directory of large logs shows their recent lines, not all of them.
+const globTailBytes = 1 << 20 // 1MB
+
+// compressedExts are skipped when watching a whole directory, which usually
+// holds rotated archives next to the live logs.
+var compressedExts = []string{".gz", ".bz2", ".xz", ".zst", ".zip"}
+
+// isRotated reports whether name looks like a rotated log such as app.log.1
+// or app.log.2024-05-01. Rotation renames the live file to it, and reading it
+// as new would show its old lines again.
+func isRotated(name string) bool {
+	if i := strings.LastIndexByte(name, '.'); i > 0 && i < len(name)-1 {
+		if strings.Trim(name[i+1:], "0123456789") == "" {
+			return true
+		}
+	}
+	return strings.Contains(name, ".log.")
+}
+
+// IsWatchPattern reports whether arg names a directory or a glob such as
+// logs/**/*.log rather than a single file.
+func IsWatchPattern(arg string) bool {
+	if info, err := os.Stat(expandHome(arg)); err == nil {
+		return info.IsDir() // A file named app[1].log is just a file
+	}
+	return strings.ContainsAny(arg, "*?[")
+}
+
+// globSource reads every file matching a directory or glob as one stream.
+// Lines are tagged with their file. Matching files are tailed as they grow,
+// and new ones are picked up as they are created.
+type globSource struct {
+	root    string   // Directory the pattern is relative to
+	segs    []string // The rest of the pattern; ** matches any directories
+	dir     bool     // Every file below root, archives and rotated logs left out
+	watcher *fsnotify.Watcher
+	pw      *io.PipeWriter
+	opened  int              // Files read when opening
+	offsets map[string]int64 // Read position of each tailed file, kept by run
+}
+
+// newGlobSource opens the files matching pattern and watches for more. It
+// returns their lines merged in time order; later lines come from Read.
+func newGlobSource(pattern string) (*globSource, []string, io.Reader, error) {
+	g := &globSource{offsets: make(map[string]int64)}
+	clean := filepath.ToSlash(filepath.Clean(expandHome(pattern)))
+	if info, err := os.Stat(clean); err == nil && info.IsDir() {
+		g.root, g.segs, g.dir = clean, []string{"**", "*"}, true
+	} else {
+		g.root, g.segs = splitGlob(clean)
+		if _, err := path.Match(strings.Join(g.segs, "/"), ""); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	g.watcher = watcher
+
+	var files []string
+	err = filepath.WalkDir(g.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Unreadable parts are skipped
+		}
+		if d.IsDir() {
+			if !g.mayContain(p) {
+				return filepath.SkipDir
+			}
+			watcher.Add(p)
+		} else if g.matches(p) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err == nil && len(files) == 0 && len(watcher.WatchList()) == 0 {
+		err = &fs.PathError{Op: "watch", Path: pattern, Err: fs.ErrNotExist}
+	}
+	if err != nil {
+		watcher.Close()
+		return nil, nil, nil, err
+	}
+
+	var all [][]string
+	for _, f := range files {
+		lines, size, err := readTail(f, globTailBytes)
+		if err != nil {
+			continue
+		}
+		g.offsets[f] = size
+		all = append(all, g.tagAll(f, lines))
+	}
+	g.opened = len(all)
+
+	pr, pw := io.Pipe()
+	g.pw = pw
+	go g.run()
+	return g, mergeByTime(all), pr, nil
+}
+
+// readTail returns the lines of the last limit bytes of a file, leaving out
+// the partial line the cut starts in, and the size it read up to.
+func readTail(p string, limit int64) ([]string, int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	size, start := info.Size(), int64(0)
+	if size > limit {
+		start = size - limit
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, start, size-start))
+	if err != nil {
+		return nil, 0, err
+	}
+	if start > 0 {
+		if i := strings.IndexByte(string(data), '\n'); i >= 0 {
+			data = data[i+1:]
+		} else {
+			data = nil
+		}
+	}
+	return splitFileLines(string(data)), size, nil
+}
+
+// splitGlob splits a slash-separated pattern into the directory before its
+// first wildcard and the segments from there on.
+func splitGlob(pattern string) (string, []string) {
+	segs := strings.Split(pattern, "/")
+	i := 0
+	for i < len(segs)-1 && !strings.ContainsAny(segs[i], "*?[") {
+		i++
+	}
+	root := strings.Join(segs[:i], "/")
+	switch {
+	case root == "" && strings.HasPrefix(pattern, "/"):
+		root = "/"
+	case root == "":
+		root = "."
+	}
+	return root, segs[i:]
+}
+
+// rel is p relative to the root, in segments.
+func (g *globSource) rel(p string) []string {
+	r, err := filepath.Rel(g.root, p)
+	if err != nil || r == "." {
+		return nil
+	}
+	return strings.Split(filepath.ToSlash(r), "/")
+}
+
+func (g *globSource) matches(p string) bool {
+	if g.dir {
+		base := filepath.Base(p)
+		ext := strings.ToLower(filepath.Ext(p))
+		if strings.HasPrefix(base, ".") || contains(compressedExts, ext) || isRotated(base) {
+			return false
+		}
+	}
+	return matchSegments(g.segs, g.rel(p), false)
+}
+
+// mayContain reports whether matching files can be in directory p. Hidden
+// directories such as .git are left out of a whole-directory watch.
+func (g *globSource) mayContain(p string) bool {
+	rel := g.rel(p)
+	if g.dir && len(rel) > 0 && strings.HasPrefix(rel[len(rel)-1], ".") {
+		return false
+	}
+	return matchSegments(g.segs, rel, true)
+}
+
+// matchSegments matches path segments against pattern segments. With prefix
+// set, it reports whether segs can start a match instead.
+func matchSegments(pat, segs []string, prefix bool) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			if prefix {
+				return true
+			}
+			for i := 0; i <= len(segs); i++ {
+				if matchSegments(pat[1:], segs[i:], false) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return prefix
+		}
+		if ok, _ := path.Match(pat[0], segs[0]); !ok {
+			return false
+		}
+		pat, segs = pat[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+// tag names a file by its path below the root.
+func (g *globSource) tag(p string) string {
+	if r := g.rel(p); len(r) > 0 {
+		return strings.Join(r, "/")
+	}
+	return filepath.Base(p)
+}
+
+func (g *globSource) tagAll(p string, lines []string) []string {
+	tag := g.tag(p)
+	for i, line := range lines {
+		lines[i] = tagLine("source", tag, line)
+	}
+	return lines
+}
+
+// mergeByTime interleaves the lines of several files by timestamp. Lines
+// without one stay with the record they continue; ties keep file order.
+func mergeByTime(files [][]string) []string {
+	type entry struct {
+		t    int64
+		line string
+	}
+	var entries []entry
+	for _, lines := range files {
+		var ix timeIndex
+		ix.extend(lines)
+		for i, line := range lines {
+			entries = append(entries, entry{ix.times[i], line})
+		}
+	}
+	if len(files) > 1 {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].t < entries[j].t })
+	}
+	merged := make([]string, len(entries))
+	for i, e := range entries {
+		merged[i] = e.line
+	}
+	return merged
+}
+
+// run tails the files until the watcher closes.
+func (g *globSource) run() {
+	defer g.pw.Close()
+	for {
+		select {
+		case ev, ok := <-g.watcher.Events:
+			if !ok {
+				return
+			}
+			if err := g.handle(ev); err != nil {
+				return // The reader is gone
+			}
+		case err, ok := <-g.watcher.Errors:
+			if !ok {
+				return
+			}
+			g.pw.CloseWithError(err)
+			return
+		}
+	}
+}
+
+// handle follows one change below the root: new directories are watched and
+// scanned, new or grown files are read from where reading stopped, and
+// removed files start over if they come back (log rotation).
+func (g *globSource) handle(ev fsnotify.Event) error {
+	switch {
+	case ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		delete(g.offsets, ev.Name)
+		return nil
+	case ev.Op&(fsnotify.Create|fsnotify.Write) == 0:
+		return nil
+	}
+
+	info, err := os.Stat(ev.Name)
+	if err != nil {
+		return nil
+	}
+	if info.IsDir() {
+		if ev.Op&fsnotify.Create == 0 || !g.mayContain(ev.Name) {
+			return nil
+		}
+		// Files may be written before the watch starts.
+		var files []string
+		filepath.WalkDir(ev.Name, func(p string, d fs.DirEntry, err error) error {
+			switch {
+			case err != nil:
+			case d.IsDir() && g.mayContain(p):
+				g.watcher.Add(p)
+			case d.IsDir():
+				return filepath.SkipDir
+			case g.matches(p):
+				files = append(files, p)
+			}
+			return nil
+		})
+		for _, f := range files {
+			if err := g.readNew(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !g.matches(ev.Name) {
+		return nil
+	}
+	return g.readNew(ev.Name)
+}
+
+// readNew sends the complete lines a file gained since the last read. A
+// file seen for the first time is read from the start, as is one that was
+// truncated.
+func (g *globSource) readNew(p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	offset := g.offsets[p]
+	if info, err := f.Stat(); err == nil && info.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil
+	}
+	end := strings.LastIndexByte(string(data), '\n') + 1 // A partial line waits
+	g.offsets[p] = offset + int64(end)
+	if end == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	for _, line := range g.tagAll(p, splitFileLines(string(data[:end]))) {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	_, err = io.WriteString(g.pw, b.String())
+	return err
+}
+
+func (g *globSource) Close() error {
+	return g.watcher.Close()
+}
+
+// newGlobStore opens the files matching pattern as one store.
+func newGlobStore(pattern string, cfg config.Config) (*lineStore, error) {
+	g, lines, reader, err := newGlobSource(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &lineStore{
+		name:     pattern,
+		lines:    lines,
+		streamer: NewStreamerWithConfig(reader, streamerConfig(cfg, pattern)),
+		glob:     g,
+	}, nil
+}
+
+// NewGlobModel shows the files matching a directory or glob pattern merged
+// into one view, and tails them and any matching file created later.
+func NewGlobModel(pattern string, cfg config.Config) (Model, error) {
+	applyConfig(cfg)
+	store, err := newGlobStore(pattern, cfg)
+	if err != nil {
+		return Model{}, err
+	}
+	m := newModelWithStore(store, cfg)
+	if msg := configStatus(cfg); msg != "" {
+		m.statusMsg = msg
+	} else {
+		m.statusMsg = watchingStatus(store)
+	}
+	return m, nil
+}
+
+func watchingStatus(s *lineStore) string {
+	n := s.glob.opened
+	if n == 1 {
+		return "Watching 1 file matching " + s.name
+	}
+	return "Watching " + formatCount(n) + " files matching " + s.name
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestMatchSegments(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		prefix, want  bool
+	}{
+		{"*.log", "api.log", false, true},
+		{"*.log", "sub/api.log", false, false},
+		{"**/*.log", "api.log", false, true},
+		{"**/*.log", "a/b/api.log", false, true},
+		{"*/app.log", "worker-1/app.log", false, true},
+		{"*/app.log", "worker-1", true, true},
+		{"*/app.log", "worker-1/tmp", true, false},
+		{"**/*.log", "a/b", true, true},
+	}
+	for _, tt := range tests {
+		if got := matchSegments(strings.Split(tt.pattern, "/"), strings.Split(tt.path, "/"), tt.prefix); got != tt.want {
+			t.Errorf("matchSegments(%q, %q, %v) = %v", tt.pattern, tt.path, tt.prefix, got)
+		}
+	}
+}
+
+func TestGlobMergesAndPicksUpNewFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("api.log", "2024-01-01 10:00:00 INFO api up\n2024-01-01 10:00:02 ERROR api down\n")
+	write("worker/1.log", "2024-01-01 10:00:01 INFO job\n{\"time\":\"2024-01-01 10:00:03\",\"msg\":\"done\"}\n")
+	write("api.log.gz", "binary")
+
+	m, err := NewGlobModel(filepath.Join(dir, "**", "*.log"), config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	want := []string{
+		"[api.log] 2024-01-01 10:00:00 INFO api up",
+		"[worker/1.log] 2024-01-01 10:00:01 INFO job",
+		"[api.log] 2024-01-01 10:00:02 ERROR api down",
+		`{"source":"worker/1.log","time":"2024-01-01 10:00:03","msg":"done"}`,
+	}
+	if strings.Join(m.filteredLines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Expected the files merged in time order, got %q", m.filteredLines)
+	}
+	if !strings.Contains(m.statusMsg, "2 files") {
+		t.Errorf("Expected the file count in the status, got %q", m.statusMsg)
+	}
+
+	// New files, in new directories too, are tailed, the whole burst.
+	write("worker/2/2.log", "2024-01-01 10:00:04 WARN new worker\n2024-01-01 10:00:05 INFO ready\n")
+	m = receiveLines(t, m, m.store.streamer, 2)
+	got := m.filteredLines[len(m.filteredLines)-2:]
+	if got[0] != "[worker/2/2.log] 2024-01-01 10:00:04 WARN new worker" || got[1] != "[worker/2/2.log] 2024-01-01 10:00:05 INFO ready" {
+		t.Errorf("Unexpected tailed lines %q", got)
+	}
+
+	if _, err := NewGlobModel(filepath.Join(dir, "missing", "*.log"), config.Default()); err == nil {
+		t.Error("Expected a pattern below a missing directory to fail")
+	}
+}
+
+func TestGlobDirectorySkipsRotatedLogs(t *testing.T) {
+	dir := t.TempDir()
+	live := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(live, []byte("INFO old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, lines, reader, err := newGlobSource(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	if len(lines) != 1 {
+		t.Fatalf("Expected the live log, got %q", lines)
+	}
+
+	// Rotate: the old file moves aside and a new one starts.
+	if err := os.Rename(live, live+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(live, []byte("INFO new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != "[app.log] INFO new\n" {
+		t.Errorf("Expected only the new file to be read, got %q", line)
+	}
+
+	for _, name := range []string{"app.log.1", "app.log.2024-05-01", "app.3"} {
+		if !isRotated(name) {
+			t.Errorf("Expected %s to count as rotated", name)
+		}
+	}
+	if isRotated("app.log") || isRotated("v1.2.log") {
+		t.Error("Expected live logs not to count as rotated")
+	}
+}
+
+// receiveLines delivers chunks from st until n more lines have arrived. It
+// fails if they do not come in time, as when a streamer holds back the end
+// of a burst.
+func receiveLines(t *testing.T, m Model, st *Streamer, n int) Model {
+	t.Helper()
+	want := len(m.store.lines) + n
+	for len(m.store.lines) < want {
+		chunks := make(chan tea.Msg, 1)
+		go func() { chunks <- WaitForStream(st)() }()
+		select {
+		case msg := <-chunks:
+			if err := msg.(LogChunkMsg).Err; err != nil {
+				t.Fatalf("Stream ended early: %v", err)
+			}
+			updated, _ := m.Update(msg)
+			m = updated.(Model)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d more lines, got %d", n, n-(want-len(m.store.lines)))
+		}
+	}
+	return m
+}
+
+func TestReadTail(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(p, []byte("first\nsecond\nthird\n"), 0o644)
+
+	lines, size, err := readTail(p, 10)
+	if err != nil || size != 19 || strings.Join(lines, ",") != "third" {
+		t.Errorf("Expected the whole lines of the last 10 bytes, got %q up to %d (%v)", lines, size, err)
+	}
+	if lines, _, _ := readTail(p, globTailBytes); len(lines) != 3 {
+		t.Errorf("Expected a small file to be read whole, got %q", lines)
+	}
+}
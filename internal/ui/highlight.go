@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+// highlightRule colors every match of re. Higher priority rules win where
+// matches overlap; scope limits the rule to one kind of line.
+type highlightRule struct {
+	re       *regexp.Regexp
+	style    lipgloss.Style
+	priority int
+	scope    string // "", json, logfmt or text
+}
+
+// sessionHighlightPriority puts :hl rules above every configured rule.
+const sessionHighlightPriority = 1 << 20
+
+// highlightRules are the configured rules, set by applyConfig.
+var highlightRules []highlightRule
+
+// sessionHighlightColors are cycled through by :hl.
+var sessionHighlightColors = []string{"#AF5FFF", "#FF8700", "#00AFAF", "#FF5FAF", "#87AF00"}
+
+// setHighlightRules compiles the configured rules. Invalid patterns are
+// skipped; Validate reports them before the UI starts.
+func setHighlightRules(hs []config.Highlight) {
+	highlightRules = nil
+	noColor := os.Getenv("NO_COLOR") != ""
+	for _, h := range hs {
+		re, err := regexp.Compile(h.Pattern)
+		if err != nil || h.Pattern == "" {
+			continue
+		}
+		style := lipgloss.NewStyle().Bold(h.Bold).Italic(h.Italic).Underline(h.Underline)
+		if noColor {
+			if h.Fg != "" || h.Bg != "" {
+				style = style.Underline(true)
+			}
+		} else {
+			if h.Fg != "" {
+				style = style.Foreground(lipgloss.Color(h.Fg))
+			}
+			if h.Bg != "" {
+				style = style.Background(lipgloss.Color(h.Bg))
+			}
+		}
+		scope := h.Scope
+		if scope == "any" {
+			scope = ""
+		}
+		highlightRules = append(highlightRules, highlightRule{re: re, style: style, priority: h.Priority, scope: scope})
+	}
+}
+
+// newSessionHighlight builds a temporary rule for :hl, picking the next color.
+func newSessionHighlight(pattern string, n int) (highlightRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return highlightRule{}, err
+	}
+	style := lipgloss.NewStyle().Bold(true)
+	if os.Getenv("NO_COLOR") != "" {
+		style = style.Underline(true)
+	} else {
+		style = style.Foreground(lipgloss.Color(sessionHighlightColors[n%len(sessionHighlightColors)]))
+	}
+	return highlightRule{re: re, style: style, priority: sessionHighlightPriority + n}, nil
+}
+
+// lineScope classifies a line for scoped rules.
+func lineScope(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") {
+		return "json"
+	}
+	if _, ok := parseLogfmtRecord(trimmed); ok {
+		return "logfmt"
+	}
+	return "text"
+}
+
+type styledSpan struct {
+	start, end int // Byte offsets into the plain text
+	style      lipgloss.Style
+}
+
+// highlightSpans resolves the rules against plain text. Ranges matched by the
+// search regex are reserved so search matches stay visible.
+func highlightSpans(plain string, rules []highlightRule, search *regexp.Regexp) []styledSpan {
+	if len(rules) == 0 {
+		return nil
+	}
+	sorted := append([]highlightRule(nil), rules...)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].priority > sorted[b].priority })
+
+	claimed := make([]bool, len(plain))
+	if search != nil {
+		for _, loc := range search.FindAllStringIndex(plain, -1) {
+			for i := loc[0]; i < loc[1]; i++ {
+				claimed[i] = true
+			}
+		}
+	}
+
+	scope := ""
+	var spans []styledSpan
+	for _, r := range sorted {
+		if r.scope != "" {
+			if scope == "" {
+				scope = lineScope(plain)
+			}
+			if r.scope != scope {
+				continue
+			}
+		}
+	matches:
+		for _, loc := range r.re.FindAllStringIndex(plain, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			for i := loc[0]; i < loc[1]; i++ {
+				if claimed[i] {
+					continue matches
+				}
+			}
+			for i := loc[0]; i < loc[1]; i++ {
+				claimed[i] = true
+			}
+			spans = append(spans, styledSpan{loc[0], loc[1], r.style})
+		}
+	}
+	sort.Slice(spans, func(a, b int) bool { return spans[a].start < spans[b].start })
+	return spans
+}
+
+// overlaySpans restyles spans of an already decorated line. Offsets refer to
+// the line without ANSI codes. Escape codes inside a span are dropped and the
+// last one is restored after it, so level and JSON coloring carry on.
+func overlaySpans(decorated string, spans []styledSpan) string {
+	if len(spans) == 0 {
+		return decorated
+	}
+	escapes := ansiRegex.FindAllStringIndex(decorated, -1)
+
+	var b strings.Builder
+	var span strings.Builder
+	active := ""
+	plainPos, si, ei := 0, 0, 0
+	inSpan := false
+
+	for i := 0; i < len(decorated); {
+		if ei < len(escapes) && escapes[ei][0] == i {
+			esc := decorated[i:escapes[ei][1]]
+			if esc == "\x1b[0m" || esc == "\x1b[m" {
+				active = ""
+			} else {
+				active += esc
+			}
+			if !inSpan {
+				b.WriteString(esc)
+			}
+			i = escapes[ei][1]
+			ei++
+			continue
+		}
+
+		if !inSpan && si < len(spans) && plainPos == spans[si].start {
+			inSpan = true
+			span.Reset()
+		}
+		if inSpan {
+			span.WriteByte(decorated[i])
+		} else {
+			b.WriteByte(decorated[i])
+		}
+		i++
+		plainPos++
+
+		if inSpan && plainPos == spans[si].end {
+			if active != "" {
+				b.WriteString("\x1b[0m")
+			}
+			b.WriteString(spans[si].style.Render(span.String()))
+			b.WriteString(active)
+			inSpan = false
+			si++
+		}
+	}
+	if inSpan {
+		b.WriteString(spans[si].style.Render(span.String()))
+	}
+	return b.String()
+}
+
+// applyHighlightRules overlays the configured and session rules on a line
+// that has already been through highlightMatches and highlightLine.
+func (m Model) applyHighlightRules(decorated string) string {
+	return m.applyHighlightRulesSlice(decorated, stripAnsi(decorated), 0)
+}
+
+// applyHighlightRulesSlice overlays the rules on the decorated part of a
+// line shown from byte offset from of plain, as in horizontal scrolling. The
+// rules are matched against the whole line, so its scope and matches that
+// cross the screen edges come out as they do in full.
+func (m Model) applyHighlightRulesSlice(decorated, plain string, from int) string {
+	if len(highlightRules) == 0 && len(m.sessionHighlights) == 0 {
+		return decorated
+	}
+	rules := append(append([]highlightRule(nil), highlightRules...), m.sessionHighlights...)
+	width := len(stripAnsi(decorated))
+	var spans []styledSpan
+	for _, sp := range highlightSpans(plain, rules, m.regex) {
+		sp.start, sp.end = max(sp.start-from, 0), min(sp.end-from, width)
+		if sp.start < sp.end {
+			spans = append(spans, sp)
+		}
+	}
+	return overlaySpans(decorated, spans)
+}
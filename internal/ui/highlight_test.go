@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestHighlightSpansPriorityAndScope(t *testing.T) {
+	low := highlightRule{re: regexp.MustCompile(`\d+`), style: lipgloss.NewStyle(), priority: 0}
+	high := highlightRule{re: regexp.MustCompile(`5\d\d`), style: lipgloss.NewStyle(), priority: 10}
+	jsonOnly := highlightRule{re: regexp.MustCompile(`GET`), style: lipgloss.NewStyle(), scope: "json"}
+
+	plain := "GET /a status=503 took=12"
+	spans := highlightSpans(plain, []highlightRule{low, high, jsonOnly}, nil)
+	var got []string
+	for _, s := range spans {
+		got = append(got, plain[s.start:s.end])
+	}
+	want := []string{"503", "12"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Expected spans %v, got %v", want, got)
+	}
+
+	spans = highlightSpans(plain, []highlightRule{low}, regexp.MustCompile(`took=12`))
+	if len(spans) != 1 || plain[spans[0].start:spans[0].end] != "503" {
+		t.Errorf("Expected search matches to be left alone, got %v", spans)
+	}
+}
+
+func TestOverlaySpansRestoresStyle(t *testing.T) {
+	decorated := "\x1b[31mERROR\x1b[0m code=503"
+	spans := []styledSpan{{start: 0, end: 3, style: lipgloss.NewStyle()}}
+
+	got := overlaySpans(decorated, spans)
+	want := "\x1b[31m\x1b[0mERR\x1b[31mOR\x1b[0m code=503"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if stripAnsi(got) != stripAnsi(decorated) {
+		t.Errorf("Expected the text to be unchanged, got %q", stripAnsi(got))
+	}
+}
+
+func TestSessionHighlightCommand(t *testing.T) {
+	m := InitialModel("test.log", []string{"a 503", "b 200"}, nil)
+
+	m = pressKeys(m, ":", "hl 5\\d\\d", "enter")
+	if len(m.sessionHighlights) != 1 {
+		t.Fatalf("Expected one session highlight, got %d", len(m.sessionHighlights))
+	}
+	if m.inputMode != ModeNormal {
+		t.Error("Expected to return to normal mode")
+	}
+
+	m = pressKeys(m, ":", "hl (", "enter")
+	if len(m.sessionHighlights) != 1 || m.statusMsg == "" {
+		t.Errorf("Expected an invalid regex to be reported, got %q", m.statusMsg)
+	}
+
+	m = pressKeys(m, ":", "nohl", "enter")
+	if len(m.sessionHighlights) != 0 {
+		t.Error("Expected :nohl to clear session highlights")
+	}
+}
+
+func TestConfigHighlightRules(t *testing.T) {
+	t.Cleanup(func() { applyConfig(config.Default()) })
+	cfg := config.Default()
+	cfg.Highlights = []config.Highlight{
+		{Pattern: `req-\w+`, Fg: "#FF0000", Priority: 2},
+		{Pattern: `level`, Scope: "json"},
+	}
+	applyConfig(cfg)
+
+	if len(highlightRules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(highlightRules))
+	}
+	if highlightRules[0].priority != 2 || highlightRules[1].scope != "json" {
+		t.Errorf("Unexpected rules: %+v", highlightRules)
+	}
+
+	cfg.Highlights = []config.Highlight{{Pattern: "("}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an invalid pattern to fail validation")
+	}
+}
+
+func TestHighlightRulesOnScrolledLine(t *testing.T) {
+	m := InitialModel("test.log", []string{"a"}, nil)
+	upper := lipgloss.NewStyle().Transform(strings.ToUpper)
+	m.sessionHighlights = []highlightRule{
+		{re: regexp.MustCompile(`code=\w+`), style: upper},
+		{re: regexp.MustCompile(`user`), style: upper, scope: "json"},
+	}
+
+	// Only part of the JSON line is on screen: the match crossing the left
+	// edge and the JSON scope still apply.
+	plain := `{"msg":"code=err","user":"bob"}`
+	from := strings.Index(plain, "err")
+	visible := plain[from : from+12]
+	if got := m.applyHighlightRulesSlice(visible, plain, from); got != `ERR","USER":` {
+		t.Errorf("Expected the rules matched on the whole line, got %q", got)
+	}
+}
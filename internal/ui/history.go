@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+const maxHistoryEntries = 200
+
+// history keeps the values entered at each prompt, oldest first, and the
+// named saved filters. It is saved to disk after every change; a zero path
+// keeps it in memory only.
+type history struct {
+	path    string
+	Entries map[string][]string    `json:"entries"`
+	Filters map[string]savedFilter `json:"filters,omitempty"`
+}
+
+// savedFilter is a named snapshot of the filter settings.
+type savedFilter struct {
+	Text   string     `json:"text,omitempty"`
+	Regex  bool       `json:"regex,omitempty"`
+	Hidden []string   `json:"hidden_levels,omitempty"` // error, warn, info, debug
+	Start  *time.Time `json:"start,omitempty"`
+	End    *time.Time `json:"end,omitempty"`
+}
+
+// historyPath is where prompt history is persisted.
+func historyPath() string {
+	dir := config.StateDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "history.json")
+}
+
+// loadHistory reads the history file. A missing or unreadable file starts an
+// empty history.
+func loadHistory(path string) *history {
+	h := &history{path: path}
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(data, h)
+		}
+	}
+	if h.Entries == nil {
+		h.Entries = make(map[string][]string)
+	}
+	if h.Filters == nil {
+		h.Filters = make(map[string]savedFilter)
+	}
+	return h
+}
+
+// add records value for prompt, moving repeats to the end.
+func (h *history) add(prompt, value string) {
+	if value == "" {
+		return
+	}
+	entries := h.Entries[prompt]
+	for i, e := range entries {
+		if e == value {
+			entries = append(entries[:i:i], entries[i+1:]...)
+			break
+		}
+	}
+	entries = append(entries, value)
+	if len(entries) > maxHistoryEntries {
+		entries = entries[len(entries)-maxHistoryEntries:]
+	}
+	h.Entries[prompt] = entries
+	h.save()
+}
+
+func (h *history) list(prompt string) []string {
+	return h.Entries[prompt]
+}
+
+func (h *history) save() {
+	if h.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(h.path, data, 0o644)
+}
+
+// Prompt names used as history keys.
+const (
+	promptCommand = "command"
+	promptFilter  = "filter"
+	promptStart   = "start"
+	promptEnd     = "end"
+	promptJump    = "jump"
+	promptColumns = "columns"
+)
+
+// promptName is the history key of an input mode.
+func promptName(mode InputMode) string {
+	switch mode {
+	case ModeFilter:
+		return promptFilter
+	case ModeSetStartDate:
+		return promptStart
+	case ModeSetEndDate:
+		return promptEnd
+	case ModeJumpTime:
+		return promptJump
+	case ModeTableColumns:
+		return promptColumns
+	case ModeCommand:
+		return promptCommand
+	}
+	return ""
+}
+
+// resetHistoryBrowse forgets the position of up/down browsing.
+func (m *Model) resetHistoryBrowse() {
+	m.historyIndex = -1
+	m.historyDraft = ""
+	m.historySearching = false
+	m.historyQuery = ""
+}
+
+// browseHistory steps through the prompt's history with up (-1) and down
+// (+1). Stepping past the newest entry restores what was being typed.
+func (m *Model) browseHistory(prompt string, delta int) {
+	entries := m.history.list(prompt)
+	if len(entries) == 0 {
+		return
+	}
+	if m.historyIndex < 0 {
+		if delta > 0 {
+			return
+		}
+		m.historyDraft = m.textInput.Value()
+		m.historyIndex = len(entries)
+	}
+
+	idx := max(0, m.historyIndex+delta)
+	if idx >= len(entries) {
+		m.textInput.SetValue(m.historyDraft)
+		m.resetHistoryBrowse()
+	} else {
+		m.historyIndex = idx
+		m.textInput.SetValue(entries[idx])
+	}
+	m.textInput.CursorEnd()
+}
+
+// handleHistoryKey handles up/down browsing and ctrl+r search at a prompt.
+// It returns false for keys the prompt itself should handle.
+func (m *Model) handleHistoryKey(msg tea.KeyMsg) bool {
+	prompt := promptName(m.inputMode)
+	if prompt == "" {
+		return false
+	}
+	if m.historySearching {
+		return m.handleHistorySearchKey(prompt, msg)
+	}
+	switch msg.String() {
+	case "up":
+		m.browseHistory(prompt, -1)
+	case "down":
+		m.browseHistory(prompt, 1)
+	case "ctrl+r":
+		m.historyDraft = m.textInput.Value()
+		m.historySearching = true
+		m.historyQuery = ""
+		m.historyIndex = len(m.history.list(prompt))
+		m.searchHistory(prompt, m.historyIndex-1)
+	default:
+		return false
+	}
+	return true
+}
+
+// handleHistorySearchKey edits the ctrl+r query. Enter accepts the match and
+// submits it; esc restores what was typed before the search.
+func (m *Model) handleHistorySearchKey(prompt string, msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyCtrlR:
+		m.searchHistory(prompt, m.historyIndex-1)
+	case tea.KeyBackspace:
+		if r := []rune(m.historyQuery); len(r) > 0 {
+			m.historyQuery = string(r[:len(r)-1])
+		}
+		m.searchHistory(prompt, len(m.history.list(prompt))-1)
+	case tea.KeyRunes, tea.KeySpace:
+		m.historyQuery += string(msg.Runes)
+		m.searchHistory(prompt, len(m.history.list(prompt))-1)
+	case tea.KeyEsc, tea.KeyCtrlG:
+		m.textInput.SetValue(m.historyDraft)
+		m.textInput.CursorEnd()
+		m.resetHistoryBrowse()
+	default:
+		// Accept the match and let the prompt handle the key (e.g. enter).
+		m.historySearching = false
+		m.historyQuery = ""
+		return false
+	}
+	return true
+}
+
+// searchHistory finds the newest entry at or before index that contains the
+// query and shows it in the prompt.
+func (m *Model) searchHistory(prompt string, from int) {
+	entries := m.history.list(prompt)
+	for i := min(from, len(entries)-1); i >= 0; i-- {
+		if strings.Contains(strings.ToLower(entries[i]), strings.ToLower(m.historyQuery)) {
+			m.historyIndex = i
+			m.textInput.SetValue(entries[i])
+			m.textInput.CursorEnd()
+			return
+		}
+	}
+}
+
+// historySearchView is the prompt shown while searching with ctrl+r.
+func (m Model) historySearchView() string {
+	return fmt.Sprintf("(history search)`%s': %s", m.historyQuery, m.textInput.Value())
+}
+
+// saveFilter stores the current filter settings under name.
+func (m *Model) saveFilter(name string) {
+	if name == "" {
+		m.statusMsg = "Usage: :save <name>"
+		return
+	}
+	f := savedFilter{Text: m.filterText, Regex: m.regexMode, Start: m.startDate, End: m.endDate}
+	for _, lvl := range []struct {
+		name  string
+		shown bool
+	}{{"error", m.showError}, {"warn", m.showWarn}, {"info", m.showInfo}, {"debug", m.showDebug}} {
+		if !lvl.shown {
+			f.Hidden = append(f.Hidden, lvl.name)
+		}
+	}
+	m.history.Filters[name] = f
+	m.history.save()
+	m.statusMsg = fmt.Sprintf("Saved filter %q", name)
+}
+
+// loadFilter restores the filter settings saved under name.
+func (m *Model) loadFilter(name string) {
+	f, ok := m.history.Filters[name]
+	if !ok {
+		m.statusMsg = fmt.Sprintf("No saved filter %q (have: %s)", name, strings.Join(m.savedFilterNames(), ", "))
+		return
+	}
+	m.filterText = f.Text
+	m.regexMode = f.Regex
+	m.startDate, m.endDate = f.Start, f.End
+	m.showError = !contains(f.Hidden, "error")
+	m.showWarn = !contains(f.Hidden, "warn")
+	m.showInfo = !contains(f.Hidden, "info")
+	m.showDebug = !contains(f.Hidden, "debug")
+	m.applyFilters(true)
+	m.statusMsg = fmt.Sprintf("Loaded filter %q", name)
+}
+
+// deleteFilter removes a saved filter.
+func (m *Model) deleteFilter(name string) {
+	if _, ok := m.history.Filters[name]; !ok {
+		m.statusMsg = fmt.Sprintf("No saved filter %q", name)
+		return
+	}
+	delete(m.history.Filters, name)
+	m.history.save()
+	m.statusMsg = fmt.Sprintf("Deleted filter %q", name)
+}
+
+func (m Model) savedFilterNames() []string {
+	names := make([]string, 0, len(m.history.Filters))
+	for name := range m.history.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func pressKeyMsg(m Model, msgs ...tea.KeyMsg) Model {
+	for _, msg := range msgs {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestPromptHistoryPerPrompt(t *testing.T) {
+	m := InitialModel("test.log", []string{"INFO a", "ERROR b"}, nil)
+	m.history = loadHistory(filepath.Join(t.TempDir(), "history.json"))
+
+	m = pressKeys(m, "/", "ERROR", "enter")
+	m = pressKeys(m, "/")
+	m.textInput.SetValue("")
+	m = pressKeys(m, "timeout", "enter")
+	m = pressKeys(m, "[", "2024-01-01", "enter")
+
+	if got := m.history.list(promptFilter); len(got) != 2 || got[1] != "timeout" {
+		t.Errorf("Expected filter history [ERROR timeout], got %v", got)
+	}
+	if got := m.history.list(promptStart); len(got) != 1 {
+		t.Errorf("Expected start-time history to be kept separately, got %v", got)
+	}
+
+	m = pressKeys(m, "/")
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.textInput.Value(); got != "ERROR" {
+		t.Errorf("Expected up to browse filter history, got %q", got)
+	}
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.textInput.Value(); got != "timeout" {
+		t.Errorf("Expected down past the newest entry to restore the draft, got %q", got)
+	}
+
+	reloaded := loadHistory(m.history.path)
+	if len(reloaded.list(promptFilter)) != 2 {
+		t.Error("Expected prompt history to persist")
+	}
+}
+
+func TestPromptHistorySearch(t *testing.T) {
+	m := InitialModel("test.log", []string{"a"}, nil)
+	m.history = loadHistory("")
+	for _, v := range []string{"user=alice", "status=500", "user=bob"} {
+		m.history.add(promptFilter, v)
+	}
+
+	m = pressKeys(m, "/")
+	m.textInput.SetValue("draft")
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = pressKeys(m, "user")
+	if got := m.textInput.Value(); got != "user=bob" {
+		t.Errorf("Expected the newest match, got %q", got)
+	}
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+	if got := m.textInput.Value(); got != "user=alice" {
+		t.Errorf("Expected ctrl+r to step to an older match, got %q", got)
+	}
+
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if got := m.textInput.Value(); got != "draft" || m.inputMode != ModeFilter {
+		t.Errorf("Expected esc to cancel the search only, got %q", got)
+	}
+
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = pressKeys(m, "500", "enter")
+	if m.filterText != "status=500" || m.inputMode != ModeNormal {
+		t.Errorf("Expected enter to accept and apply the match, got %q", m.filterText)
+	}
+}
+
+func TestSavedFilters(t *testing.T) {
+	lines := []string{"INFO a", "ERROR b", "DEBUG c"}
+	m := InitialModel("test.log", lines, nil)
+	m.history = loadHistory(filepath.Join(t.TempDir(), "history.json"))
+
+	m = pressKeys(m, "4")
+	m = runCommandLine(m, "filter b")
+	m = runCommandLine(m, "save errs")
+	m = pressKeys(m, "esc", "4", "j")
+	if len(m.filteredLines) != 3 {
+		t.Fatalf("Expected filters to be cleared, got %v", m.filteredLines)
+	}
+
+	m = runCommandLine(m, "load errs")
+	if m.filterText != "b" || m.showDebug || len(m.filteredLines) != 1 {
+		t.Errorf("Expected the saved filter to be restored, got %q debug=%v", m.filterText, m.showDebug)
+	}
+
+	if _, ok := loadHistory(m.history.path).Filters["errs"]; !ok {
+		t.Error("Expected the saved filter to persist")
+	}
+
+	m = runCommandLine(m, "delete errs")
+	if len(m.savedFilterNames()) != 0 {
+		t.Error("Expected :delete to remove the filter")
+	}
+}
@@ -0,0 +1,424 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyMap holds the normal-mode bindings and those of the table view, visual
+// selection and the focused detail pane. Every binding has a config name (see
+// actions) so it can be remapped from the [keys] table.
+type KeyMap struct {
+	Help    key.Binding
+	Quit    key.Binding
+	Cancel  key.Binding
+	Command key.Binding
+	Palette key.Binding
+
+	Up           key.Binding
+	Down         key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	PercentJump  key.Binding
+	Left         key.Binding
+	Right        key.Binding
+	Follow       key.Binding
+	JumpTime     key.Binding
+	Bookmark     key.Binding
+	NextBookmark key.Binding
+	PrevBookmark key.Binding
+
+	Filter       key.Binding
+	Regex        key.Binding
+	StartDate    key.Binding
+	EndDate      key.Binding
+	ClearFilters key.Binding
+	ToggleError  key.Binding
+	ToggleWarn   key.Binding
+	ToggleInfo   key.Binding
+	ToggleDebug  key.Binding
+
+	Wrap        key.Binding
+	Fold        key.Binding
+	Collapse    key.Binding
+	Table       key.Binding
+	Detail      key.Binding
+	DetailFocus key.Binding
+	Visual      key.Binding
+	VisualLine  key.Binding
+	Copy        key.Binding
+	Timeline    key.Binding
+	Export      key.Binding
+	Redact      key.Binding
+	LineNumbers key.Binding
+	NextTab     key.Binding
+	PrevTab     key.Binding
+	SwitchPane  key.Binding
+	Trace       key.Binding
+	Alerts      key.Binding
+	Restart     key.Binding
+	Undo        key.Binding
+	Redo        key.Binding
+
+	// Table view
+	TableNextColumn key.Binding
+	TablePrevColumn key.Binding
+	TableMoveLeft   key.Binding
+	TableMoveRight  key.Binding
+	TableHideColumn key.Binding
+	TableSort       key.Binding
+	TableColumns    key.Binding
+
+	// Visual selection
+	VisualDown      key.Binding
+	VisualUp        key.Binding
+	VisualLeft      key.Binding
+	VisualRight     key.Binding
+	VisualWordNext  key.Binding
+	VisualWordPrev  key.Binding
+	VisualLineStart key.Binding
+	VisualLineEnd   key.Binding
+	VisualTop       key.Binding
+	VisualBottom    key.Binding
+	VisualChars     key.Binding
+	VisualLines     key.Binding
+	VisualCopy      key.Binding
+	VisualCancel    key.Binding
+
+	// Focused detail pane
+	DetailUp     key.Binding
+	DetailDown   key.Binding
+	DetailTop    key.Binding
+	DetailBottom key.Binding
+	DetailFold   key.Binding
+	DetailCopy   key.Binding
+	DetailClose  key.Binding
+}
+
+// keyAction ties a binding to its config name and help group.
+type keyAction struct {
+	name    string
+	group   string
+	binding *key.Binding
+}
+
+// Help groups, in display order. The mode groups apply only in their mode,
+// where they take precedence over the normal-mode keys.
+const (
+	keyGroupGeneral    = "General"
+	keyGroupNavigation = "Navigation"
+	keyGroupFiltering  = "Filtering"
+	keyGroupView       = "View & Tools"
+
+	keyGroupTable  = "Table View"
+	keyGroupVisual = "Visual Selection"
+	keyGroupDetail = "Detail Pane"
+)
+
+// modeGroups are the key groups of modes; every other group is normal mode.
+var modeGroups = []string{keyGroupTable, keyGroupVisual, keyGroupDetail}
+
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp("", desc))
+}
+
+// DefaultKeyMap returns the built-in bindings. Space is the " " key string.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Help:    binding("Toggle Help", "?"),
+		Quit:    binding("Quit", "q", "ctrl+c"),
+		Cancel:  binding("Close / Clear Filters", "esc"),
+		Command: binding("Command Line", ":"),
+		Palette: binding("Action Palette", "ctrl+p"),
+
+		Up:           binding("Cursor Up", "k", "up"),
+		Down:         binding("Cursor Down", "j", "down"),
+		HalfPageUp:   binding("Half Page Up", "ctrl+u"),
+		HalfPageDown: binding("Half Page Down", "d", "ctrl+d"),
+		PageUp:       binding("Page Up", "b", "pgup", "ctrl+b"),
+		PageDown:     binding("Page Down", " ", "pgdown", "ctrl+f"),
+		Top:          binding("Go to Top", "g", "home"),
+		Bottom:       binding("Go to Bottom", "G", "end"),
+		PercentJump:  binding("Go to N% (after a count)", "%"),
+		Left:         binding("Scroll Left", "h", "left"),
+		Right:        binding("Scroll Right", "l", "right"),
+		Follow:       binding("Toggle Follow", "f"),
+		JumpTime:     binding("Jump to Time", "J"),
+		Bookmark:     binding("Toggle Bookmark", "m"),
+		NextBookmark: binding("Next Bookmark", "n"),
+		PrevBookmark: binding("Previous Bookmark", "N"),
+
+		Filter:       binding("Filter Logs", "/"),
+		Regex:        binding("Regex Toggle", "R"),
+		StartDate:    binding("Set Start Time", "["),
+		EndDate:      binding("Set End Time", "]"),
+		ClearFilters: binding("Clear Filters", "c"),
+		ToggleError:  binding("Toggle ERROR", "1"),
+		ToggleWarn:   binding("Toggle WARN", "2"),
+		ToggleInfo:   binding("Toggle INFO", "3"),
+		ToggleDebug:  binding("Toggle DEBUG", "4"),
+
+		Wrap:        binding("Toggle Wrap", "w"),
+		Fold:        binding("Fold Stack Traces", "z"),
+		Collapse:    binding("Collapse Repeated Lines", "D"),
+		Table:       binding("Table View (JSON/logfmt)", "T"),
+		Detail:      binding("Detail Pane", "enter"),
+		DetailFocus: binding("Focus Detail Pane", "F"),
+		Visual:      binding("Visual Select (chars)", "v"),
+		VisualLine:  binding("Visual Select (lines)", "V"),
+		Copy:        binding("Copy Selection / Line", "y"),
+		Timeline:    binding("Toggle Timeline", "t"),
+		Export:      binding("Export View to File", "ctrl+s"),
+		Redact:      binding("Redact / Reveal Secrets", "P"),
+		LineNumbers: binding("Toggle Line Numbers", "#"),
+		NextTab:     binding("Next Tab", "gt"),
+		PrevTab:     binding("Previous Tab", "gT"),
+		SwitchPane:  binding("Switch Split Pane", "ctrl+w"),
+		Trace:       binding("Follow Trace ID", "*"),
+		Alerts:      binding("Alerts Panel", "A"),
+		Restart:     binding("Restart Command", "r"),
+		Undo:        binding("Undo Filter Change", "u"),
+		Redo:        binding("Redo Filter Change", "ctrl+r"),
+
+		TableNextColumn: binding("Next Column", "tab"),
+		TablePrevColumn: binding("Previous Column", "shift+tab"),
+		TableMoveLeft:   binding("Move Column Left", "<"),
+		TableMoveRight:  binding("Move Column Right", ">"),
+		TableHideColumn: binding("Hide Column", "x"),
+		TableSort:       binding("Sort by Column", "S"),
+		TableColumns:    binding("Choose Columns", "C"),
+
+		VisualDown:      binding("Extend Down", "j", "down"),
+		VisualUp:        binding("Extend Up", "k", "up"),
+		VisualLeft:      binding("Extend Left", "h", "left"),
+		VisualRight:     binding("Extend Right", "l", "right"),
+		VisualWordNext:  binding("Next Word", "w"),
+		VisualWordPrev:  binding("Previous Word", "b"),
+		VisualLineStart: binding("Start of Line", "0", "home"),
+		VisualLineEnd:   binding("End of Line", "$", "end"),
+		VisualTop:       binding("First Line", "g"),
+		VisualBottom:    binding("Last Line", "G"),
+		VisualChars:     binding("Select Characters", "v"),
+		VisualLines:     binding("Select Lines", "V"),
+		VisualCopy:      binding("Copy Selection", "y"),
+		VisualCancel:    binding("Cancel Selection", "esc"),
+
+		DetailUp:     binding("Previous Field", "k", "up"),
+		DetailDown:   binding("Next Field", "j", "down"),
+		DetailTop:    binding("First Field", "g", "home"),
+		DetailBottom: binding("Last Field", "G", "end"),
+		DetailFold:   binding("Fold / Unfold Field", "enter", " "),
+		DetailCopy:   binding("Copy Field", "y"),
+		DetailClose:  binding("Unfocus Detail Pane", "esc"),
+	}
+}
+
+// actions lists every binding with its config name, in help order.
+func (k *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"help", keyGroupGeneral, &k.Help},
+		{"quit", keyGroupGeneral, &k.Quit},
+		{"cancel", keyGroupGeneral, &k.Cancel},
+		{"command", keyGroupGeneral, &k.Command},
+		{"palette", keyGroupGeneral, &k.Palette},
+
+		{"up", keyGroupNavigation, &k.Up},
+		{"down", keyGroupNavigation, &k.Down},
+		{"half_page_up", keyGroupNavigation, &k.HalfPageUp},
+		{"half_page_down", keyGroupNavigation, &k.HalfPageDown},
+		{"page_up", keyGroupNavigation, &k.PageUp},
+		{"page_down", keyGroupNavigation, &k.PageDown},
+		{"top", keyGroupNavigation, &k.Top},
+		{"bottom", keyGroupNavigation, &k.Bottom},
+		{"percent_jump", keyGroupNavigation, &k.PercentJump},
+		{"left", keyGroupNavigation, &k.Left},
+		{"right", keyGroupNavigation, &k.Right},
+		{"follow", keyGroupNavigation, &k.Follow},
+		{"jump_time", keyGroupNavigation, &k.JumpTime},
+		{"bookmark", keyGroupNavigation, &k.Bookmark},
+		{"next_bookmark", keyGroupNavigation, &k.NextBookmark},
+		{"prev_bookmark", keyGroupNavigation, &k.PrevBookmark},
+
+		{"filter", keyGroupFiltering, &k.Filter},
+		{"regex", keyGroupFiltering, &k.Regex},
+		{"start_date", keyGroupFiltering, &k.StartDate},
+		{"end_date", keyGroupFiltering, &k.EndDate},
+		{"clear_filters", keyGroupFiltering, &k.ClearFilters},
+		{"toggle_error", keyGroupFiltering, &k.ToggleError},
+		{"toggle_warn", keyGroupFiltering, &k.ToggleWarn},
+		{"toggle_info", keyGroupFiltering, &k.ToggleInfo},
+		{"toggle_debug", keyGroupFiltering, &k.ToggleDebug},
+
+		{"wrap", keyGroupView, &k.Wrap},
+		{"fold", keyGroupView, &k.Fold},
+		{"collapse", keyGroupView, &k.Collapse},
+		{"table", keyGroupView, &k.Table},
+		{"detail", keyGroupView, &k.Detail},
+		{"detail_focus", keyGroupView, &k.DetailFocus},
+		{"visual", keyGroupView, &k.Visual},
+		{"visual_line", keyGroupView, &k.VisualLine},
+		{"copy", keyGroupView, &k.Copy},
+		{"timeline", keyGroupView, &k.Timeline},
+		{"export", keyGroupView, &k.Export},
+		{"redact", keyGroupView, &k.Redact},
+		{"line_numbers", keyGroupView, &k.LineNumbers},
+		{"next_tab", keyGroupView, &k.NextTab},
+		{"prev_tab", keyGroupView, &k.PrevTab},
+		{"switch_pane", keyGroupView, &k.SwitchPane},
+		{"trace", keyGroupFiltering, &k.Trace},
+		{"alerts", keyGroupView, &k.Alerts},
+		{"restart", keyGroupView, &k.Restart},
+		{"undo", keyGroupFiltering, &k.Undo},
+		{"redo", keyGroupFiltering, &k.Redo},
+
+		{"table_next_column", keyGroupTable, &k.TableNextColumn},
+		{"table_prev_column", keyGroupTable, &k.TablePrevColumn},
+		{"table_move_left", keyGroupTable, &k.TableMoveLeft},
+		{"table_move_right", keyGroupTable, &k.TableMoveRight},
+		{"table_hide_column", keyGroupTable, &k.TableHideColumn},
+		{"table_sort", keyGroupTable, &k.TableSort},
+		{"table_columns", keyGroupTable, &k.TableColumns},
+
+		{"visual_down", keyGroupVisual, &k.VisualDown},
+		{"visual_up", keyGroupVisual, &k.VisualUp},
+		{"visual_left", keyGroupVisual, &k.VisualLeft},
+		{"visual_right", keyGroupVisual, &k.VisualRight},
+		{"visual_word_next", keyGroupVisual, &k.VisualWordNext},
+		{"visual_word_prev", keyGroupVisual, &k.VisualWordPrev},
+		{"visual_line_start", keyGroupVisual, &k.VisualLineStart},
+		{"visual_line_end", keyGroupVisual, &k.VisualLineEnd},
+		{"visual_top", keyGroupVisual, &k.VisualTop},
+		{"visual_bottom", keyGroupVisual, &k.VisualBottom},
+		{"visual_chars", keyGroupVisual, &k.VisualChars},
+		{"visual_lines", keyGroupVisual, &k.VisualLines},
+		{"visual_copy", keyGroupVisual, &k.VisualCopy},
+		{"visual_cancel", keyGroupVisual, &k.VisualCancel},
+
+		{"detail_up", keyGroupDetail, &k.DetailUp},
+		{"detail_down", keyGroupDetail, &k.DetailDown},
+		{"detail_top", keyGroupDetail, &k.DetailTop},
+		{"detail_bottom", keyGroupDetail, &k.DetailBottom},
+		{"detail_fold", keyGroupDetail, &k.DetailFold},
+		{"detail_copy", keyGroupDetail, &k.DetailCopy},
+		{"detail_close", keyGroupDetail, &k.DetailClose},
+	}
+}
+
+// sequenceActions may be bound to two-key sequences such as "gt"; they are
+// matched by handleKeySequence, every other binding by single keys only.
+var sequenceActions = []string{"next_tab", "prev_tab"}
+
+// keyNames are the names Bubble Tea gives keys that are not characters, e.g.
+// "ctrl+a" or "pgdown".
+var keyNames = func() map[string]bool {
+	names := make(map[string]bool)
+	for t := tea.KeyF20; t <= tea.KeyBackspace; t++ {
+		if s := t.String(); s != "" {
+			names[s] = true
+		}
+	}
+	return names
+}()
+
+// singleKey reports whether k names one key press rather than a sequence.
+func singleKey(k string) bool {
+	k = strings.TrimPrefix(k, "alt+")
+	return utf8.RuneCountInString(k) == 1 || keyNames[k]
+}
+
+// NewKeyMap returns the default bindings with overrides applied. Each override
+// replaces all keys of the named action; an empty list unbinds it. Unknown
+// action names, key sequences outside sequenceActions and keys bound to two
+// actions of the same mode are errors.
+func NewKeyMap(overrides map[string][]string) (KeyMap, error) {
+	km := DefaultKeyMap()
+	actions := km.actions()
+
+	byName := make(map[string]*key.Binding, len(actions))
+	for _, a := range actions {
+		byName[a.name] = a.binding
+	}
+	for name, keys := range overrides {
+		b, ok := byName[name]
+		if !ok {
+			return DefaultKeyMap(), fmt.Errorf("unknown key action %q", name)
+		}
+		normalized := make([]string, len(keys))
+		for i, k := range keys {
+			normalized[i] = normalizeKey(k)
+			if !singleKey(normalized[i]) && !contains(sequenceActions, name) {
+				return DefaultKeyMap(), fmt.Errorf("key %q of %s is not a single key; only %s take sequences", k, name, strings.Join(sequenceActions, " and "))
+			}
+		}
+		b.SetKeys(normalized...)
+		b.SetEnabled(len(normalized) > 0)
+	}
+
+	owner := make(map[string]string) // Mode and key to action
+	for _, a := range actions {
+		for _, k := range a.binding.Keys() {
+			mk := a.mode() + "\x00" + k
+			if other, ok := owner[mk]; ok {
+				return DefaultKeyMap(), fmt.Errorf("key %q is bound to both %s and %s", displayKey(k), other, a.name)
+			}
+			owner[mk] = a.name
+		}
+	}
+	// The detail pane takes its focus key before the table view sees it, so
+	// it may not share one with a table action either.
+	for _, k := range km.DetailFocus.Keys() {
+		if other, ok := owner[keyGroupTable+"\x00"+k]; ok {
+			return DefaultKeyMap(), fmt.Errorf("key %q is bound to both detail_focus and %s", displayKey(k), other)
+		}
+	}
+	return km, nil
+}
+
+// mode is the key group of a mode binding, or "" for normal mode.
+func (a keyAction) mode() string {
+	if contains(modeGroups, a.group) {
+		return a.group
+	}
+	return ""
+}
+
+// normalizeKey maps config spellings to Bubble Tea key strings.
+func normalizeKey(k string) string {
+	k = strings.TrimSpace(k)
+	if strings.EqualFold(k, "space") {
+		return " "
+	}
+	return k
+}
+
+func displayKey(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+// firstKey is the main key of a binding for short hints, "-" when unbound.
+func firstKey(b key.Binding) string {
+	if keys := b.Keys(); len(keys) > 0 {
+		return displayKey(keys[0])
+	}
+	return "-"
+}
+
+// helpKeys renders the keys of a binding for the help view, e.g. "k/up".
+func helpKeys(b key.Binding) string {
+	keys := make([]string, len(b.Keys()))
+	for i, k := range b.Keys() {
+		keys[i] = displayKey(k)
+	}
+	return strings.Join(keys, "/")
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestNewKeyMapOverrides(t *testing.T) {
+	km, err := NewKeyMap(map[string][]string{
+		"page_down": {"space", "x"},
+		"timeline":  {},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyMap: %v", err)
+	}
+	if got := strings.Join(km.PageDown.Keys(), ","); got != " ,x" {
+		t.Errorf("Expected page_down keys [space x], got %q", got)
+	}
+	if km.Timeline.Enabled() {
+		t.Error("Expected an empty key list to unbind timeline")
+	}
+	if got := helpKeys(km.PageDown); got != "space/x" {
+		t.Errorf("Expected help keys space/x, got %q", got)
+	}
+}
+
+func TestNewKeyMapErrors(t *testing.T) {
+	if _, err := NewKeyMap(map[string][]string{"teleport": {"x"}}); err == nil {
+		t.Error("Expected an error for an unknown action")
+	}
+	if _, err := NewKeyMap(map[string][]string{"wrap": {"f"}}); err == nil {
+		t.Error("Expected an error for a key bound to two actions")
+	}
+	if _, err := NewKeyMap(map[string][]string{"detail_focus": {"tab"}}); err == nil {
+		t.Error("Expected an error for a detail focus key the table view also uses")
+	}
+	if _, err := NewKeyMap(map[string][]string{"wrap": {"ww"}}); err == nil {
+		t.Error("Expected an error for a key sequence outside the tab actions")
+	}
+	if _, err := NewKeyMap(map[string][]string{"wrap": {"ctrl+x", "alt+w"}, "next_tab": {"gn"}}); err != nil {
+		t.Errorf("Expected named keys and tab sequences to be accepted, got %v", err)
+	}
+	if _, err := NewKeyMap(nil); err != nil {
+		t.Errorf("Expected the default keymap to be free of conflicts, got %v", err)
+	}
+}
+
+func TestRemappedKeyInModel(t *testing.T) {
+	t.Cleanup(func() { applyConfig(config.Default()) })
+	cfg := config.Default()
+	cfg.Keys = map[string][]string{"wrap": {"W"}}
+	m := NewModel("test.log", []string{"a", "b"}, nil, cfg)
+
+	m = pressKeys(m, "w")
+	if m.wrap {
+		t.Error("Expected the old wrap key to be unbound")
+	}
+	m = pressKeys(m, "W")
+	if !m.wrap {
+		t.Error("Expected the remapped key to toggle wrap")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if !updated.(Model).regexMode {
+		t.Error("Expected R to toggle regex mode")
+	}
+
+	help := stripAnsi(m.helpView())
+	if !regexp.MustCompile(`W\s+Toggle Wrap`).MatchString(help) {
+		t.Errorf("Expected help to list the remapped key, got:\n%s", help)
+	}
+}
+
+func TestModeKeys(t *testing.T) {
+	// Modes may reuse normal-mode keys, but not their own.
+	if _, err := NewKeyMap(map[string][]string{"table_sort": {"w"}}); err != nil {
+		t.Errorf("Expected a table key to shadow a normal key, got %v", err)
+	}
+	if _, err := NewKeyMap(map[string][]string{"table_sort": {"x"}}); err == nil {
+		t.Error("Expected an error for a key bound to two table actions")
+	}
+
+	t.Cleanup(func() { applyConfig(config.Default()) })
+	cfg := config.Default()
+	cfg.Keys = map[string][]string{"visual_copy": {"Y"}, "detail_down": {"J"}}
+	m := NewModel("test.log", []string{"INFO a", "INFO b"}, nil, cfg)
+	m.screenWidth = 200
+
+	m = pressKeys(m, "V", "y")
+	if m.visualMode == visualNone {
+		t.Fatal("Expected the old copy key not to end the selection")
+	}
+	m = pressKeys(m, "Y")
+	if m.visualMode != visualNone {
+		t.Error("Expected the remapped copy key to end the selection")
+	}
+
+	help := stripAnsi(m.helpView())
+	for _, want := range []string{"Visual Selection", `Y\s+Copy Selection`, `J\s+Next Field`, `S\s+Sort by Column`} {
+		if !regexp.MustCompile(want).MatchString(help) {
+			t.Errorf("Expected help to list %q, got:\n%s", want, help)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+type logLevel int
+
+const (
+	levelError logLevel = iota
+	levelWarn
+	levelInfo
+	levelDebug
+)
+
+// levelKeywords holds the words identifying each level, in level order.
+// Overridden from the [levels] config section.
+var levelKeywords = [...][]string{
+	levelError: {"ERROR"},
+	levelWarn:  {"WARN"},
+	levelInfo:  {"INFO"},
+	levelDebug: {"DEBUG"},
+}
+
+func setLevelKeywords(l config.Levels) {
+	for lvl, words := range [...][]string{l.Error, l.Warn, l.Info, l.Debug} {
+		if len(words) > 0 {
+			levelKeywords[lvl] = words
+		}
+	}
+}
+
+// hasLevel reports whether line contains one of the keywords for lvl.
+func hasLevel(line string, lvl logLevel) bool {
+	_, ok := levelKeyword(line, lvl)
+	return ok
+}
+
+// levelKeyword returns the first keyword of lvl found in line.
+func levelKeyword(line string, lvl logLevel) (string, bool) {
+	for _, kw := range levelKeywords[lvl] {
+		if strings.Contains(line, kw) {
+			return kw, true
+		}
+	}
+	return "", false
+}
+
+func levelStyle(lvl logLevel) lipgloss.Style {
+	switch lvl {
+	case levelError:
+		return errorStyle
+	case levelWarn:
+		return warnStyle
+	case levelInfo:
+		return infoStyleLog
+	}
+	return debugStyle
+}
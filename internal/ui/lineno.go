@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// countTimeout is how long a first digit waits for the rest of a count
+// before it runs its own binding.
+const countTimeout = 500 * time.Millisecond
+
+// countTimeoutMsg ends the wait for the count started as countGen.
+type countTimeoutMsg struct{ gen int }
+
+// handleCountKey collects a numeric prefix for NG (go to line N) and N%
+// (go to N percent of the file). The first digit is held back: when a second
+// digit, G or % follows, it is part of the count; when another key follows,
+// or nothing within countTimeout, it runs its own binding, so 1-4 toggle
+// levels as before. It returns true when the key was consumed.
+func (m *Model) handleCountKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if m.countReplay {
+		m.countReplay = false
+		return false, nil
+	}
+	s := msg.String()
+	if len(s) == 1 && s[0] >= '0' && s[0] <= '9' {
+		if m.count == "" {
+			if s == "0" {
+				return false, nil
+			}
+			m.count = s
+			m.countGen++
+			gen := m.countGen
+			return true, tea.Tick(countTimeout, func(time.Time) tea.Msg { return countTimeoutMsg{gen} })
+		}
+		m.count += s
+		return true, nil
+	}
+	if m.count == "" {
+		return false, nil
+	}
+
+	n, _ := strconv.Atoi(m.count)
+	switch {
+	case key.Matches(msg, m.keys.Bottom):
+		m.gotoLine(n)
+	case key.Matches(msg, m.keys.PercentJump):
+		m.gotoPercent(n)
+	case len(m.count) == 1:
+		// Not a count after all: the digit runs, then this key.
+		cmd := m.runHeldDigit()
+		updated, next := m.update(msg)
+		if um, ok := updated.(Model); ok {
+			*m = um
+		}
+		return true, tea.Batch(cmd, next)
+	default:
+		m.count = ""
+		return false, nil
+	}
+	m.count = ""
+	return true, nil
+}
+
+// runHeldDigit runs the binding of a first digit that did not start a count.
+func (m *Model) runHeldDigit() tea.Cmd {
+	digit := m.count
+	m.count = ""
+	m.countReplay = true
+	updated, cmd := m.update(keyMsgFromString(digit))
+	if um, ok := updated.(Model); ok {
+		*m = um
+	}
+	return cmd
+}
+
+// handleCountTimeout runs a lone first digit once no count followed it.
+func (m Model) handleCountTimeout(msg countTimeoutMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.countGen || len(m.count) != 1 {
+		return m, nil
+	}
+	digit := m.count
+	m.count = ""
+	m.countReplay = true
+	return m.trackUndo(keyMsgFromString(digit))
+}
+
+// gotoLine moves the cursor to original line n (1-based), or the first
+// visible line after it when n is filtered out.
+func (m *Model) gotoLine(n int) {
+	if len(m.filteredLines) == 0 {
+		return
+	}
+	target := n - 1
+	var row int
+	if !m.tableMode || m.tableSortCol == "" {
+		// Rows keep file order, so the refs are sorted.
+		row = sort.Search(len(m.filteredRefs), func(i int) bool { return m.filteredRefs[i].last >= target })
+	} else {
+		row = len(m.filteredRefs)
+		for i, ref := range m.filteredRefs {
+			if ref.first <= target && ref.last >= target {
+				row = i
+				break
+			}
+		}
+	}
+	if row >= len(m.filteredLines) {
+		row = len(m.filteredLines) - 1
+		m.statusMsg = fmt.Sprintf("Line %d is past the end (%d lines)", n, len(m.originalLines))
+	}
+	m.setCursor(row)
+	m.centerCursor()
+}
+
+// gotoPercent moves to the line p percent of the way through the file.
+func (m *Model) gotoPercent(p int) {
+	p = max(0, min(p, 100))
+	m.gotoLine(max(1, (len(m.originalLines)*p+99)/100))
+}
+
+// centerCursor scrolls so the cursor line sits in the middle of the page.
+func (m *Model) centerCursor() {
+	h := m.pageHeight()
+	m.yOffset = max(0, min(m.cursor-h/2, len(m.filteredLines)-h))
+}
+
+// lineNumberWidth is the width of the line-number gutter, 0 when it is off,
+// plus the stream mark of a command's output.
+func (m Model) lineNumberWidth() int {
+	w := m.streamMarkWidth()
+	if m.lineNumbers {
+		w += len(strconv.Itoa(len(m.originalLines))) + 1
+	}
+	return w
+}
+
+// lineNumber renders the original file line number of a row for the gutter,
+// followed by the stream mark.
+func (m Model) lineNumber(row int) string {
+	mark := m.streamMark(row)
+	if !m.lineNumbers {
+		return mark
+	}
+	w := len(strconv.Itoa(len(m.originalLines))) + 1
+	if row < 0 || row >= len(m.filteredRefs) {
+		return strings.Repeat(" ", w) + mark
+	}
+	return mutedStyle.Render(fmt.Sprintf("%*d ", w-1, m.filteredRefs[row].first+1)) + mark
+}
+
+// gutterWidth is the number of cells before the log text in no-wrap mode.
+func (m Model) gutterWidth() int {
+	return 3 + m.lineNumberWidth()
+}
+
+// toggleLineNumbers shows or hides the line-number gutter.
+func (m *Model) toggleLineNumbers() {
+	m.lineNumbers = !m.lineNumbers
+	m.layoutCache = make(map[int][]string)
+}
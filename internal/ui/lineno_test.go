@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		level := "INFO"
+		if i%10 == 0 {
+			level = "ERROR"
+		}
+		lines[i] = fmt.Sprintf("%s line %d", level, i+1)
+	}
+	return lines
+}
+
+func TestCountGoto(t *testing.T) {
+	m := InitialModel("test.log", numberedLines(200), nil)
+	m.viewport.Height = 10
+	m.bookmarks[3] = struct{}{}
+
+	m = pressKeys(m, "1", "5", "0", "G")
+	if got := m.filteredLines[m.cursor]; got != "INFO line 150" {
+		t.Fatalf("Expected 150G to land on line 150, got %q", got)
+	}
+	if !m.showError || len(m.undoStack) != 0 || len(m.bookmarks) != 1 {
+		t.Error("Expected the first digit of the count not to toggle ERROR")
+	}
+	if m.cursor < m.yOffset || m.cursor >= m.yOffset+m.pageHeight() {
+		t.Error("Expected the target line to be on screen")
+	}
+
+	m = pressKeys(m, "5", "0", "%")
+	if got := m.filteredLines[m.cursor]; got != "INFO line 100" {
+		t.Errorf("Expected 50%% to land on line 100, got %q", got)
+	}
+	m.keys, _ = NewKeyMap(map[string][]string{"percent_jump": {"p"}})
+	m = pressKeys(m, "2", "5", "p")
+	if got := m.filteredLines[m.cursor]; got != "INFO line 50" {
+		t.Errorf("Expected a remapped percent key to land on line 50, got %q", got)
+	}
+	m.keys = DefaultKeyMap()
+
+	// A lone digit still toggles its level.
+	m = pressKeys(m, "1", "j")
+	if m.showError || m.count != "" {
+		t.Error("Expected 1 followed by another key to toggle ERROR")
+	}
+
+	// So does one nothing follows, once the count times out.
+	m = pressKeys(m, "1")
+	if m.showError {
+		t.Fatal("Expected the digit to wait for a count")
+	}
+	updated, _ := m.Update(countTimeoutMsg{m.countGen})
+	m = updated.(Model)
+	if !m.showError || m.count != "" || len(m.undoStack) != 2 {
+		t.Errorf("Expected the timeout to toggle ERROR back, undo %d", len(m.undoStack))
+	}
+}
+
+func TestGotoFilteredLine(t *testing.T) {
+	m := InitialModel("test.log", numberedLines(200), nil)
+	m.viewport.Height = 10
+
+	m = runCommandLine(m, "filter ERROR")
+	m = runCommandLine(m, "45")
+	if got := m.filteredLines[m.cursor]; got != "ERROR line 51" {
+		t.Errorf("Expected :45 to land on the next visible line, got %q", got)
+	}
+
+	m = runCommandLine(m, "999")
+	if m.cursor != len(m.filteredLines)-1 || !strings.Contains(m.statusMsg, "past the end") {
+		t.Errorf("Expected a line past the end to go to the last line, got %d %q", m.cursor, m.statusMsg)
+	}
+}
+
+func TestLineNumberGutter(t *testing.T) {
+	m := InitialModel("test.log", numberedLines(200), nil)
+	m.viewport.Height = 10
+	m.screenWidth = 80
+
+	m = runCommandLine(m, "filter ERROR")
+	m = pressKeys(m, "#")
+	if got := stripAnsi(m.gutter(2)); got != " 21    " {
+		t.Errorf("Expected the original line number in the gutter, got %q", got)
+	}
+	if m.gutterWidth() != 7 {
+		t.Errorf("Expected the gutter to widen for line numbers, got %d", m.gutterWidth())
+	}
+
+	m = runCommandLine(m, "set nonumber")
+	if m.gutter(2) != "   " {
+		t.Errorf("Expected :set nonumber to hide the numbers, got %q", m.gutter(2))
+	}
+}
+
+func TestLineNumberGutterWrap(t *testing.T) {
+	m := InitialModel("test.log", []string{"alpha line", "beta line"}, nil)
+	m.viewport.Height = 10
+	m.screenWidth = 80
+	m.wrap = true
+	m.lineNumbers = true
+	m.bookmarks[1] = struct{}{}
+
+	body := stripAnsi(m.bodyView())
+	if !strings.Contains(body, "1 alpha line") || !strings.Contains(body, "2 🔖 beta line") {
+		t.Fatalf("Expected one gutter per wrapped row, got %q", body)
+	}
+	if strings.Count(body, "2 ") != 1 {
+		t.Errorf("Expected the line number to be drawn once, got %q", body)
+	}
+
+	// "2 🔖 " takes five cells before the text.
+	if line, col := m.resolvePos(5, 1); line != 1 || col != 0 {
+		t.Errorf("Expected a click on the text to land on 1:0, got %d:%d", line, col)
+	}
+}
@@ -0,0 +1,378 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+// maxDatagram is the largest syslog or JSON datagram read at once.
+const maxDatagram = 64 * 1024
+
+// Listener receives log lines from clients on a socket (lv --listen). Each
+// line is tagged with the client it came from and can be read like stdin.
+type Listener struct {
+	name   string
+	pr     *io.PipeReader
+	pw     *io.PipeWriter
+	stream net.Listener   // tcp and unix
+	packet net.PacketConn // udp and unixgram
+	path   string         // Socket file of a unixgram listener
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// Listen starts receiving on addr, one of tcp://host:port, udp://host:port,
+// unix:///path or unixgram:///path. Port 0 picks a free port.
+func Listen(addr string) (*Listener, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	l := &Listener{conns: make(map[net.Conn]struct{})}
+	switch u.Scheme {
+	case "tcp":
+		l.stream, err = net.Listen("tcp", u.Host)
+	case "udp":
+		l.packet, err = net.ListenPacket("udp", u.Host)
+	case "unix":
+		if err = removeStaleSocket("unix", u.Path); err == nil {
+			l.stream, err = net.Listen("unix", u.Path)
+		}
+	case "unixgram":
+		if err = removeStaleSocket("unixgram", u.Path); err == nil {
+			l.packet, err = net.ListenPacket("unixgram", u.Path)
+		}
+		l.path = u.Path
+	default:
+		return nil, fmt.Errorf("unsupported address %q (use tcp://, udp://, unix:// or unixgram://)", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l.name = addr
+	switch {
+	case u.Scheme == "tcp":
+		l.name = "tcp://" + l.stream.Addr().String()
+	case u.Scheme == "udp":
+		l.name = "udp://" + l.packet.LocalAddr().String()
+	}
+	l.pr, l.pw = io.Pipe()
+	if l.stream != nil {
+		go l.accept()
+	} else {
+		go l.receive()
+	}
+	return l, nil
+}
+
+// removeStaleSocket removes a socket file left by an earlier run. A socket
+// something still listens on is left alone and reported as in use.
+func removeStaleSocket(network, path string) error {
+	if info, err := os.Stat(path); err != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	c, err := net.Dial(network, path)
+	if err == nil {
+		c.Close()
+		return fmt.Errorf("%s: address in use", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("%s: address in use (%w)", path, err)
+	}
+	return os.Remove(path)
+}
+
+// String is the address the listener receives on.
+func (l *Listener) String() string {
+	return l.name
+}
+
+// Read returns the tagged lines of every client, newline-terminated.
+func (l *Listener) Read(p []byte) (int, error) {
+	return l.pr.Read(p)
+}
+
+// Close stops listening and disconnects the clients. Reading then ends.
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	for c := range l.conns {
+		c.Close()
+	}
+	l.mu.Unlock()
+
+	var err error
+	if l.stream != nil {
+		err = l.stream.Close()
+	} else {
+		err = l.packet.Close()
+		if l.path != "" {
+			os.Remove(l.path)
+		}
+	}
+	l.pw.Close()
+	return err
+}
+
+func (l *Listener) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// accept serves stream clients until the listener closes.
+func (l *Listener) accept() {
+	clients := 0
+	for {
+		conn, err := l.stream.Accept()
+		if err != nil {
+			if !l.isClosed() {
+				l.pw.CloseWithError(err)
+			}
+			return
+		}
+		clients++
+		peer := conn.RemoteAddr().String()
+		if peer == "" || peer == "@" {
+			peer = "client " + strconv.Itoa(clients) // Unix clients have no address
+		}
+
+		l.mu.Lock()
+		if l.closed {
+			l.mu.Unlock()
+			conn.Close()
+			return
+		}
+		l.conns[conn] = struct{}{}
+		l.mu.Unlock()
+
+		go l.serve(conn, peer)
+	}
+}
+
+// serve reads the lines of one stream client.
+func (l *Listener) serve(conn net.Conn, peer string) {
+	defer func() {
+		l.mu.Lock()
+		delete(l.conns, conn)
+		l.mu.Unlock()
+		conn.Close()
+	}()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if l.emit(peer, scanner.Text()) != nil {
+			return
+		}
+	}
+}
+
+// receive reads datagrams until the listener closes. A datagram holds one
+// syslog message, or one or more JSON lines.
+func (l *Listener) receive() {
+	buf := make([]byte, maxDatagram)
+	for {
+		n, addr, err := l.packet.ReadFrom(buf)
+		if err != nil {
+			if !l.isClosed() {
+				l.pw.CloseWithError(err)
+			}
+			return
+		}
+		peer := "client"
+		if addr != nil && addr.String() != "" {
+			peer = addr.String()
+		}
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			if l.emit(peer, line) != nil {
+				return
+			}
+		}
+	}
+}
+
+// emit writes one received line, tagged with its peer. Each write is one
+// whole line, so lines of concurrent clients do not interleave.
+func (l *Listener) emit(peer, line string) error {
+	line = strings.TrimRight(line, "\r\x00")
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	_, err := io.WriteString(l.pw, tagReceived(peer, line)+"\n")
+	return err
+}
+
+// tagReceived adds the peer to a received line. Syslog messages are
+// rewritten as readable log lines first.
+func tagReceived(peer, line string) string {
+	if msg, ok := parseSyslog(line); ok {
+		line = msg
+	}
+	return tagLine("peer", peer, line)
+}
+
+// tagLine marks a line with its source: as field first in a JSON object,
+// which keeps it JSON, otherwise as a [tag] prefix.
+func tagLine(field, tag, line string) string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") && json.Valid([]byte(trimmed)) {
+		key, _ := json.Marshal(field)
+		value, _ := json.Marshal(tag)
+		rest := strings.TrimSpace(trimmed[1:])
+		if rest == "}" {
+			return "{" + string(key) + ":" + string(value) + "}"
+		}
+		return "{" + string(key) + ":" + string(value) + "," + rest
+	}
+	return "[" + tag + "] " + line
+}
+
+// syslogLevels maps syslog severities 0-7 to the log levels lv colors.
+var syslogLevels = [8]logLevel{levelError, levelError, levelError, levelError, levelWarn, levelInfo, levelInfo, levelDebug}
+
+// parseSyslog rewrites an RFC 5424 or RFC 3164 message as
+// "<time> <LEVEL> <host> <app>[<pid>]: <message>", using the configured
+// keyword of the level its severity maps to.
+func parseSyslog(line string) (string, bool) {
+	if !strings.HasPrefix(line, "<") {
+		return "", false
+	}
+	end := strings.IndexByte(line, '>')
+	if end < 2 || end > 4 {
+		return "", false
+	}
+	pri, err := strconv.Atoi(line[1:end])
+	if err != nil || pri < 0 || pri > 191 {
+		return "", false
+	}
+	level := levelKeywords[syslogLevels[pri%8]][0]
+	rest := line[end+1:]
+
+	if strings.HasPrefix(rest, "1 ") {
+		return parseSyslog5424(level, rest[2:])
+	}
+	return parseSyslog3164(level, rest)
+}
+
+// parseSyslog5424 reads TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD [MSG].
+func parseSyslog5424(level, s string) (string, bool) {
+	fields := strings.SplitN(s, " ", 6)
+	if len(fields) < 6 {
+		if len(fields) == 5 {
+			fields = append(fields, "-")
+		} else {
+			return "", false
+		}
+	}
+	sd, msg := splitStructuredData(fields[5])
+	msg = strings.TrimPrefix(msg, "\ufeff") // UTF-8 BOM
+
+	var parts []string
+	if ts := nilValue(fields[0], ""); ts != "" {
+		parts = append(parts, ts)
+	}
+	parts = append(parts, level)
+	if host := nilValue(fields[1], ""); host != "" {
+		parts = append(parts, host)
+	}
+	app := nilValue(fields[2], "")
+	if pid := nilValue(fields[3], ""); pid != "" {
+		app += "[" + pid + "]"
+	}
+	if id := nilValue(fields[4], ""); id != "" {
+		app = strings.TrimSpace(app + " " + id)
+	}
+	if sd != "" {
+		app = strings.TrimSpace(app + " " + sd)
+	}
+	if app != "" {
+		parts = append(parts, app+":")
+	}
+	return strings.TrimSpace(strings.Join(parts, " ") + " " + msg), true
+}
+
+// splitStructuredData splits the STRUCTURED-DATA of a message from its text.
+func splitStructuredData(s string) (sd, msg string) {
+	if strings.HasPrefix(s, "-") {
+		return "", strings.TrimPrefix(strings.TrimPrefix(s, "-"), " ")
+	}
+	i := 0
+	for i < len(s) && s[i] == '[' {
+		for i++; i < len(s) && s[i] != ']'; i++ {
+			if s[i] == '\\' {
+				i++ // Escaped ", ] or \
+			}
+		}
+		i++
+	}
+	i = min(i, len(s))
+	return s[:i], strings.TrimPrefix(s[i:], " ")
+}
+
+func nilValue(s, none string) string {
+	if s == "-" {
+		return none
+	}
+	return s
+}
+
+// parseSyslog3164 reads "Mmm dd hh:mm:ss [HOST] TAG: MSG". The timestamp has
+// no year; the current one is assumed.
+func parseSyslog3164(level, s string) (string, bool) {
+	if len(s) < len(time.Stamp)+1 {
+		return "", false
+	}
+	t, err := time.ParseInLocation(time.Stamp, s[:len(time.Stamp)], timeLocation)
+	if err != nil {
+		return "", false
+	}
+	t = t.AddDate(time.Now().Year(), 0, 0)
+	rest := strings.TrimPrefix(s[len(time.Stamp):], " ")
+
+	host := ""
+	if first, after, ok := strings.Cut(rest, " "); ok && !strings.HasSuffix(first, ":") && !strings.Contains(first, "[") {
+		host, rest = first, after // Local senders leave the host out
+	}
+	parts := []string{t.Format("2006-01-02T15:04:05"), level}
+	if host != "" {
+		parts = append(parts, host)
+	}
+	return strings.Join(parts, " ") + " " + rest, true
+}
+
+// NewListenModel shows the lines clients send to l as they arrive, following
+// them unless follow is off.
+func NewListenModel(l *Listener, cfg config.Config) Model {
+	applyConfig(cfg)
+	store := &lineStore{
+		name:     l.String(),
+		streamer: NewStreamerWithConfig(l, streamerConfig(cfg, "Stdin")),
+		listener: l,
+	}
+	m := newModelWithStore(store, cfg)
+	m.following = cfg.Follow != "off"
+	if msg := configStatus(cfg); msg != "" {
+		m.statusMsg = msg
+	}
+	return m
+}
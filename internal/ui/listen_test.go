@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestParseSyslog(t *testing.T) {
+	year := time.Now().Year()
+	tests := []struct {
+		in, want string
+	}{
+		{`<165>1 2024-05-01T10:00:00.123Z web01 api 4211 ID47 [origin ip="10.0.0.1"] request failed`,
+			`2024-05-01T10:00:00.123Z INFO web01 api[4211] ID47 [origin ip="10.0.0.1"]: request failed`},
+		{`<11>1 2024-05-01T10:00:00Z - app - - - ` + "\ufeff" + `disk full`,
+			`2024-05-01T10:00:00Z ERROR app: disk full`},
+		{`<12>Mar  3 14:02:01 db01 postgres[88]: slow query`,
+			fmt.Sprintf(`%d-03-03T14:02:01 WARN db01 postgres[88]: slow query`, year)},
+		{`<15>Mar 13 14:02:01 worker: tick`,
+			fmt.Sprintf(`%d-03-13T14:02:01 DEBUG worker: tick`, year)},
+	}
+	for _, tt := range tests {
+		got, ok := parseSyslog(tt.in)
+		if !ok || got != tt.want {
+			t.Errorf("parseSyslog(%q) = %q, %v; want %q", tt.in, got, ok, tt.want)
+		}
+	}
+	for _, in := range []string{"plain text", "<999>1 x", "<13>not a date at all"} {
+		if _, ok := parseSyslog(in); ok {
+			t.Errorf("Expected %q not to parse as syslog", in)
+		}
+	}
+}
+
+func TestTagReceived(t *testing.T) {
+	if got := tagReceived("10.0.0.5:4000", `{"level":"info","msg":"hi"}`); got != `{"peer":"10.0.0.5:4000","level":"info","msg":"hi"}` {
+		t.Errorf("Expected the peer as a JSON field, got %q", got)
+	}
+	if got := tagReceived("client 1", "INFO started"); got != "[client 1] INFO started" {
+		t.Errorf("Expected a peer prefix, got %q", got)
+	}
+}
+
+func TestListenReceivesFromClients(t *testing.T) {
+	for _, addr := range []string{"udp://127.0.0.1:0", "tcp://127.0.0.1:0", "unix://" + filepath.Join(t.TempDir(), "lv.sock")} {
+		t.Run(addr, func(t *testing.T) {
+			l, err := Listen(addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			network, target, _ := strings.Cut(l.String(), "://")
+			conn, err := net.Dial(network, target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			fmt.Fprint(conn, "<14>1 2024-05-01T10:00:00Z host app - - - hello\n")
+
+			line, err := bufio.NewReader(l).ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "] 2024-05-01T10:00:00Z INFO host app: hello\n") {
+				t.Errorf("Unexpected line %q", line)
+			}
+		})
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lv.sock")
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	l, err := Listen("unix://" + path)
+	if err != nil {
+		t.Fatalf("Expected a socket nothing listens on to be replaced, got %v", err)
+	}
+	defer l.Close()
+
+	if _, err := Listen("unix://" + path); err == nil || !strings.Contains(err.Error(), "address in use") {
+		t.Errorf("Expected a socket in use to be left alone, got %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the live socket to stay, got %v", err)
+	}
+}
+
+func TestListenBurstArrives(t *testing.T) {
+	l, err := Listen("tcp://127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewListenModel(l, config.Default())
+	defer m.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(l.String(), "tcp://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO one\nWARN two\n")
+
+	m = receiveLines(t, m, m.store.streamer, 2)
+	if len(m.filteredLines) != 2 || !strings.HasSuffix(m.filteredLines[1], "] WARN two") {
+		t.Errorf("Expected both lines while the client stays connected, got %q", m.filteredLines)
+	}
+}
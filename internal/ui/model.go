@@ -2,38 +2,50 @@ package ui
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
 	"strings"
 	"time"
 
-	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/fsnotify/fsnotify"
 	"github.com/mattn/go-runewidth"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
 	"math"
-	"os"
 	"sort"
 	"unicode/utf8"
 )
 
+// Styles are (re)assigned from the active theme by applyTheme.
 var (
-	titleStyle = func() lipgloss.Style {
+	baseTitleStyle = func() lipgloss.Style {
 		b := lipgloss.RoundedBorder()
 		b.Right = "├"
 		return lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
 	}()
 
-	infoStyle = func() lipgloss.Style {
+	baseInfoStyle = func() lipgloss.Style {
 		b := lipgloss.RoundedBorder()
 		b.Left = "┤"
-		return titleStyle.BorderStyle(b)
+		return baseTitleStyle.BorderStyle(b)
 	}()
 
+	titleStyle = baseTitleStyle
+	infoStyle  = baseInfoStyle
+
+	// Header / Footer / Help Styles
+	footerStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	helpKeyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	// Muted Style (fold summaries, badges, hints)
+	mutedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	foldSummaryStyle = mutedStyle.Italic(true)
+
 	// Log Level Styles
 	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Bold(true)
 	warnStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Bold(true)
@@ -55,6 +67,15 @@ type Point struct {
 	Y int
 }
 
+// lineRef maps a displayed row back to the range of originalLines it covers.
+// Folded and collapsed rows span several original lines.
+type lineRef struct {
+	first, last int
+	// repeat is the badge of a collapsed run ("×3 [10:00:00 → 10:00:10]"),
+	// drawn after the row but never part of its text.
+	repeat string
+}
+
 type InputMode int
 
 const (
@@ -63,6 +84,9 @@ const (
 	ModeSetStartDate
 	ModeSetEndDate
 	ModeJumpTime
+	ModeTableColumns
+	ModeCommand
+	ModePalette
 )
 
 type Model struct {
@@ -101,49 +125,142 @@ type Model struct {
 	endDate   *time.Time
 
 	// Virtualization
-	filteredLines  []string // Replaces content/originalLines for display (this is the SOURCE of truth for viewport)
+	filteredLines  []string  // Replaces content/originalLines for display (this is the SOURCE of truth for viewport)
+	filteredRefs   []lineRef // Parallel to filteredLines
 	yOffset        int
 	viewportHeight int
 
 	// Live Tailing
 	following bool
-	fileSize  int64
-	watcher   *fsnotify.Watcher
+	paused    bool // Left follow mode: new lines wait in the store
+
+	// Lines shared with other tabs on the same source
+	store *lineStore
+
+	// Tabs: snapshots of every tab's view; the active slot is stale while
+	// it is shown. Empty with a single tab.
+	tabs       []Model
+	activeTab  int
+	pendingKey string // First key of a sequence such as gt
+	pendingPos [2]int // yOffset and cursor before it
+
+	// Split panes: the other pane's view, when split
+	split         []Model
+	splitVertical bool // Side by side rather than stacked
+	splitFirst    bool // The focused pane is the top or left one
+	timeSync      bool // Keep the other pane at the focused pane's time
+	winWidth      int
+	winHeight     int
+
+	// Alerts: hits shared by the whole window, and the panel listing them
+	alerts      *alertState
+	showAlerts  bool
+	alertCursor int
+
+	// Rows of the view per level, counted up to levelsCounted
+	levelCounts   [4]int
+	levelsCounted int
 
 	// Folding
 	foldStackTraces bool
 
+	// Repeat Collapsing
+	collapseDuplicates bool
+
+	// Table View
+	tableMode     bool
+	tableColumns  []string
+	tableCursor   int // Selected column
+	tableSortCol  string
+	tableSortDesc bool
+
 	// Timeline
 	showTimeline     bool
 	timelineViewport viewport.Model
 
 	// Bookmarks
-	bookmarks map[int]struct{}
-
-	// Help
+	bookmarks     map[int]struct{}
+	bookmarkNotes map[int]string
+
+	// Line numbers and the pending NG / N% count
+	lineNumbers bool
+	count       string
+	countGen    int  // Tells the timeout of this count from earlier ones
+	countReplay bool // The next key is a held digit running its binding
+
+	// Cursor & Detail Pane
+	cursor       int // Index into filteredLines
+	cursorX      int // Rune column on the cursor line (visual mode)
+	visualMode   visualMode
+	visualAnchor Point
+	showDetail   bool
+	detailFocus  bool
+	detailCursor int
+	detailScroll int
+	detailFolds  map[string]bool // Folded JSON paths
+
+	// Redaction (display, copy and export; originalLines stay intact)
+	redact bool
+
+	// User Highlights
+	sessionHighlights []highlightRule // Added with :hl, dropped on exit
+
+	// Command Line, Palette & Prompt History
+	cfg              config.Config // Effective config, for :theme and completion
+	history          *history
+	historyIndex     int // Position while browsing with up/down, -1 when not
+	historyDraft     string
+	historyQuery     string // ctrl+r search text
+	historySearching bool
+	paletteCursor    int
+
+	// Undo / Redo of view states
+	undoStack      []viewState
+	redoStack      []viewState
+	skipUndoRecord bool // Set by undo/redo so the change is not recorded again
+
+	// Help & Key Bindings
 	showHelp bool
+	keys     KeyMap
 
-	// Streamer
-	streamer *Streamer
+	// Clipboard, Export & Status
+	clipboardMode ClipboardMode
+	osc52         string // A terminal clipboard copy the next frame carries
+	osc52Gen      int
+	exportDir     string
+	statusMsg     string // One-shot footer message, cleared on the next key
 
 	// Cache
 	layoutCache map[int][]string
 }
 
+// InitialModel creates a model with the built-in default settings.
 func InitialModel(filename string, lines []string, reader io.Reader) Model {
-	var streamer *Streamer
-	if reader != nil {
-		cfg := StreamerConfig{
-			BatchLines: 200,
-			FlushEvery: 50 * time.Millisecond,
-		}
-		// File startup backfill should favor throughput over ultra-low latency.
-		if filename != "Stdin" {
-			cfg.BatchLines = 5000
-			cfg.FlushEvery = 100 * time.Millisecond
-		}
-		streamer = NewStreamerWithConfig(reader, cfg)
+	return NewModel(filename, lines, reader, config.Default())
+}
+
+// NewModel creates a model using the settings from cfg.
+func NewModel(filename string, lines []string, reader io.Reader, cfg config.Config) Model {
+	applyConfig(cfg)
+	m := newModelWithStore(newLineStore(filename, lines, reader, cfg), cfg)
+	if msg := configStatus(cfg); msg != "" {
+		m.statusMsg = msg
+	}
+	return m
+}
+
+// configStatus reports problems with cfg for the footer: an invalid [keys]
+// table, or settings the config files could not apply.
+func configStatus(cfg config.Config) string {
+	if _, err := NewKeyMap(cfg.Keys); err != nil {
+		return "Keys: " + err.Error()
 	}
+	return strings.Join(cfg.Warnings, "; ")
+}
+
+// newModelWithStore creates a view of store, for the first tab or a new one.
+func newModelWithStore(store *lineStore, cfg config.Config) Model {
+	keys, _ := NewKeyMap(cfg.Keys)
 
 	ti := textinput.New()
 	ti.Placeholder = "Filter logs..."
@@ -153,23 +270,11 @@ func InitialModel(filename string, lines []string, reader io.Reader) Model {
 	// Highlighting will be applied lazily in View()
 	// highlighted := highlightLog(content)
 
-	// Get initial file size for watcher
-	var fileSize int64
-	f, err := os.Stat(filename)
-	if err == nil {
-		fileSize = f.Size()
-	}
-
-	// Initialize Watcher
-	watcher, _ := fsnotify.NewWatcher()
-	if watcher != nil {
-		watcher.Add(filename)
-	}
-
 	m := Model{
-		filename:      filename,
-		originalLines: lines,
-		filteredLines: lines, // Initially all lines
+		filename:      store.name,
+		store:         store,
+		originalLines: store.lines,
+		filteredLines: store.lines, // Initially all lines
 		headerHeight:  3,
 		footerHeight:  3,
 		textInput:     ti,
@@ -180,21 +285,35 @@ func InitialModel(filename string, lines []string, reader io.Reader) Model {
 		showDebug:     true,
 		regexMode:     false,
 
-		selectionStart:  nil,
-		selectionEnd:    nil,
-		xOffset:         0,
-		yOffset:         0,
-		screenWidth:     0,
-		wrap:            false,
-		following:       streamer != nil && filename == "Stdin", // Auto-follow only for stdin streams
-		fileSize:        fileSize,
-		watcher:         watcher,
-		foldStackTraces: false,
-		showTimeline:    false,
-		bookmarks:       make(map[int]struct{}),
-		showHelp:        false,
-		streamer:        streamer,
-		layoutCache:     make(map[int][]string),
+		selectionStart:     nil,
+		selectionEnd:       nil,
+		xOffset:            0,
+		yOffset:            0,
+		screenWidth:        0,
+		wrap:               cfg.Wrap,
+		lineNumbers:        cfg.Numbers,
+		following:          cfg.Follow == "on" || (cfg.Follow == "auto" && store.streamer != nil && store.name == "Stdin"), // Auto-follow only for stdin streams
+		foldStackTraces:    cfg.Fold,
+		collapseDuplicates: cfg.Collapse,
+		tableMode:          cfg.Table,
+		tableColumns:       cfg.Columns,
+		showTimeline:       false,
+		bookmarks:          make(map[int]struct{}),
+		bookmarkNotes:      make(map[int]string),
+		detailFolds:        make(map[string]bool),
+		clipboardMode:      ClipboardMode(cfg.OSC52),
+		exportDir:          cfg.ExportDir(),
+		showHelp:           false,
+		keys:               keys,
+		cfg:                cfg,
+		history:            loadHistory(historyPath()),
+		historyIndex:       -1,
+		redact:             cfg.Redact.Enabled,
+		layoutCache:        make(map[int][]string),
+		alerts:             newAlertState(),
+	}
+	if m.tableMode && len(m.tableColumns) == 0 {
+		m.tableColumns = detectColumns(store.lines)
 	}
 	m.applyFilters(true)
 	return m
@@ -202,66 +321,105 @@ func InitialModel(filename string, lines []string, reader io.Reader) Model {
 
 func (m Model) Init() tea.Cmd {
 	// Start Input Blink AND File Watcher
-	cmds := []tea.Cmd{textinput.Blink}
-	if m.watcher != nil {
-		cmds = append(cmds, WaitForFileChange(m.watcher, m.filename, m.fileSize))
-	}
-	if m.streamer != nil {
-		cmds = append(cmds, WaitForStream(m.streamer))
+	cmds := []tea.Cmd{textinput.Blink, statsTick()}
+	for _, s := range m.stores() {
+		cmds = append(cmds, s.wait())
 	}
 	return tea.Batch(cmds...)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		if msg, ok = m.routeMouse(mouse); !ok {
+			return m, nil
+		}
+	}
+
+	var updated tea.Model
+	var cmd tea.Cmd
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		updated, cmd = m.trackUndo(keyMsg)
+	} else {
+		updated, cmd = m.update(msg)
+	}
+	if next, ok := updated.(Model); ok {
+		if next.timeSync {
+			next.syncPaneTime()
+		}
+		return next, tea.Batch(cmd, next.releaseHeld(), next.clearOSC52(m.osc52Gen))
+	}
+	return updated, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var (
 		cmd  tea.Cmd
 		cmds []tea.Cmd
 	)
 
+	if msg, ok := msg.(osc52DoneMsg); ok {
+		if msg.gen == m.osc52Gen {
+			m.osc52 = ""
+		}
+		return m, nil
+	}
+	if m.handleAlertMsg(msg) {
+		return m, nil
+	}
+	if cmd, ok := m.handleProcessMsg(msg); ok {
+		return m, cmd
+	}
+	if _, ok := msg.(statsTickMsg); ok {
+		return m, statsTick()
+	}
+	if msg, ok := msg.(countTimeoutMsg); ok {
+		return m.handleCountTimeout(msg)
+	}
+
 	// Handle File Changes
 	if msg, ok := msg.(FileChangeMsg); ok {
-		if msg.Error != nil {
-			// Handle error?
-		} else if msg.NewContent != "" {
-			// Append new content
-			newLines := splitIncomingContent(msg.NewContent)
-			m.appendIncomingLines(newLines)
-
-			m.fileSize = msg.NewOffset
-
-			// Auto-scroll if following
-			if m.following {
-				// Virtualized goto bottom
-				m.yOffset = len(m.filteredLines) - m.viewport.Height
-				if m.yOffset < 0 {
-					m.yOffset = 0
-				}
+		// Route to the tab store of the file; a closed tab stops watching.
+		s := m.findStore(func(s *lineStore) bool { return s.watcher != nil && s.name == msg.Filename })
+		if s != nil {
+			if msg.Error != nil {
+				m.sourceFailed(s, msg.Error)
+			} else if msg.NewContent != "" {
+				s.err = nil
+				cmds = append(cmds, m.receiveLines(s, splitIncomingContent(msg.NewContent)))
+				s.fileSize = msg.NewOffset
+			}
+			// Continue watching, unless paused views hold enough lines
+			if m.holding(s) {
+				s.heldWatch = true
+				m.hold(s)
+			} else {
+				cmds = append(cmds, WaitForFileChange(s.watcher, s.name, s.fileSize))
 			}
-		}
-		// Continue watching
-		if m.watcher != nil {
-			cmds = append(cmds, WaitForFileChange(m.watcher, m.filename, m.fileSize))
 		}
 	}
 
 	// Handle Log Chunks (Streaming)
 	if msg, ok := msg.(LogChunkMsg); ok {
-		if msg.Err != nil {
-			// EOF or error?
-		} else if len(msg.Lines) > 0 {
-			m.appendIncomingLines(msg.Lines)
-
-			if m.following {
-				m.yOffset = len(m.filteredLines) - m.viewport.Height
-				if m.yOffset < 0 {
-					m.yOffset = 0
+		s := m.store
+		if msg.stream != nil {
+			s = m.findStore(func(s *lineStore) bool { return s.streamer == msg.stream })
+		}
+		if s != nil {
+			if msg.Err != nil {
+				m.sourceFailed(s, msg.Err)
+			} else if len(msg.Lines) > 0 {
+				cmds = append(cmds, m.receiveLines(s, msg.Lines))
+			}
+			// Continue stream loop until it ends
+			if s.streamer != nil && !errors.Is(msg.Err, io.EOF) {
+				if m.holding(s) {
+					s.heldStreams = append(s.heldStreams, s.streamer)
+					m.hold(s)
+				} else {
+					cmds = append(cmds, WaitForStream(s.streamer))
 				}
 			}
 		}
-		// Continue stream loop
-		if m.streamer != nil {
-			cmds = append(cmds, WaitForStream(m.streamer))
-		}
 	}
 
 	// Handle resize independently
@@ -270,6 +428,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if msg, ok := msg.(tea.WindowSizeMsg); ok {
 		verticalMarginHeight := m.headerHeight + m.footerHeight
 		m.screenWidth = msg.Width
+		m.winWidth, m.winHeight = msg.Width, msg.Height
 
 		if !m.ready {
 			m.viewport = viewport.New(msg.Width, msg.Height-verticalMarginHeight)
@@ -286,6 +445,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Invalidate cache on resize
 		m.layoutCache = make(map[int][]string)
+		m.layoutPanes()
 
 		// Return early to avoid m.viewport.Update(msg) resetting Width to msg.Width
 		return m, nil
@@ -302,7 +462,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// So we need to subtract 3 from visual X to get logical X.
 			gutterOffset := 0
 			if !m.wrap {
-				gutterOffset = 3
+				gutterOffset = m.gutterWidth()
 			}
 
 			logicalX := msg.X + m.xOffset - gutterOffset
@@ -318,6 +478,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.selecting = true
 						m.selectionStart = &Point{X: targetX, Y: targetLine}
 						m.selectionEnd = &Point{X: targetX, Y: targetLine}
+						m.setCursor(targetLine)
+						m.cursorX = targetX
+						m.visualMode = visualNone
 					}
 				} else if msg.Action == tea.MouseActionMotion && msg.Button == tea.MouseButtonLeft && m.selecting {
 					targetLine, targetX := m.resolvePos(msg.X, msg.Y-m.headerHeight)
@@ -335,9 +498,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.inputMode != ModeNormal {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			if m.inputMode == ModePalette {
+				return m.updatePalette(msg)
+			}
+			if m.handleHistoryKey(msg) {
+				return m, nil
+			}
+			if m.inputMode == ModeCommand && msg.String() == "tab" {
+				m.completeCommand()
+				return m, nil
+			}
 			switch msg.String() {
 			case "enter":
 				val := m.textInput.Value()
+				m.history.add(promptName(m.inputMode), val)
+
+				if m.inputMode == ModeCommand {
+					m.inputMode = ModeNormal
+					m.textInput.Blur()
+					return m, m.runCommand(val)
+				}
 
 				if m.inputMode == ModeFilter {
 					m.filterText = val
@@ -360,58 +540,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							m.endDate = &t
 						}
 					}
+				} else if m.inputMode == ModeTableColumns {
+					if cols := parseColumnList(val); len(cols) > 0 {
+						m.tableColumns = cols
+						if m.tableCursor >= len(cols) {
+							m.tableCursor = len(cols) - 1
+						}
+					}
 				} else if m.inputMode == ModeJumpTime {
 					// Jump to Time Logic
 					if val != "" {
-						target, err := parseDate(val)
-						// Heuristic: If parsing fails or assumes year 0, try combining with first log line date
-						if err != nil || target.Year() == 0 {
-							// Try to interpret as HH:MM or HH:MM:SS relative to first log line
-							// Get base date
-							if len(m.filteredLines) > 0 {
-								// Simple: split first line
-								firstLine := m.filteredLines[0]
-								if base, ok := extractDate(firstLine); ok {
-									// Try to parse val as HH:MM:SS
-									// We can use a custom parser or try strict formats
-									// Simple approach: Replace timestamp in base with val?
-									// Or parse val as time.Time (0000-01-01 HH:MM partial) and join.
-
-									// Let's rely on time.Parse for just time formats
-									timeFormats := []string{"15:04", "15:04:05", "3:04PM"}
-									var timeComponent time.Time
-									parsedTime := false
-									for _, tf := range timeFormats {
-										if tc, err := time.Parse(tf, val); err == nil {
-											timeComponent = tc
-											parsedTime = true
-											break
-										}
-									}
-
-									if parsedTime {
-										// Combine base YYYY-MM-DD with timeComponent HH:MM:SS
-										year, month, day := base.Date()
-										hour, min, sec := timeComponent.Clock()
-										target = time.Date(year, month, day, hour, min, sec, 0, base.Location())
-										err = nil // Success
-									}
-								}
-							}
-						}
-
-						if err == nil {
-							// Search for first line >= target
-							lines := m.filteredLines
-							for i, line := range lines {
-								if t, ok := extractDate(line); ok {
-									if !t.Before(target) {
-										m.viewport.YOffset = i
-										break
-									}
-								}
-							}
+						m.inputMode = ModeNormal
+						m.textInput.Blur()
+						if target, err := m.parseTargetTime(val); err == nil {
+							m.jumpToTime(target)
+						} else {
+							m.statusMsg = fmt.Sprintf("Cannot parse time %q", val)
 						}
+						return m, nil
 					}
 				}
 
@@ -437,27 +583,77 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.statusMsg = ""
+
 		if m.showHelp {
-			if msg.String() == "esc" || msg.String() == "?" || msg.String() == "q" {
+			if key.Matches(msg, m.keys.Help, m.keys.Quit, m.keys.Cancel) {
 				m.showHelp = false
 			}
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "?":
+		if m.showAlerts {
+			m.handleAlertsKey(msg)
+			return m, nil
+		}
+
+		if m.visualMode != visualNone && m.handleVisualKey(msg) {
+			return m, nil
+		}
+
+		if m.showDetail {
+			if key.Matches(msg, m.keys.DetailFocus) {
+				m.detailFocus = !m.detailFocus
+				return m, nil
+			}
+			if m.detailFocus && m.handleDetailKey(msg) {
+				return m, nil
+			}
+		}
+
+		if handled, cmd := m.handleCountKey(msg); handled {
+			return m, cmd
+		}
+
+		if m.handleKeySequence(msg) {
+			return m, nil
+		}
+
+		if m.tableMode {
+			if handled, cmd := m.handleTableKey(msg); handled {
+				return m, cmd
+			}
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Help):
 			m.showHelp = !m.showHelp
 			return m, nil
-		case "q", "ctrl+c":
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
-		case "y":
-			m.copySelection()
+		case key.Matches(msg, m.keys.Copy):
+			if m.selectionStart != nil && m.selectionEnd != nil {
+				m.copySelection()
+			} else {
+				m.copyCursorLine()
+			}
+			return m, nil
+
+		// Visual Selection
+		case key.Matches(msg, m.keys.Visual):
+			m.startVisual(visualChar)
+			return m, nil
+		case key.Matches(msg, m.keys.VisualLine):
+			m.startVisual(visualLine)
 			return m, nil
 
-		case "esc":
+		case key.Matches(msg, m.keys.Cancel):
 			if m.selectionStart != nil {
-				m.selectionStart = nil
-				m.selectionEnd = nil
+				m.exitVisual()
+				return m, nil
+			}
+			if m.showDetail {
+				m.showDetail = false
 				return m, nil
 			}
 			// clear all filters
@@ -466,14 +662,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.endDate = nil
 			m.applyFilters(true)
 
-		case "/":
+		case key.Matches(msg, m.keys.Command):
+			m.openCommandLine()
+			return m, textinput.Blink
+		case key.Matches(msg, m.keys.Palette):
+			m.openPalette()
+			return m, textinput.Blink
+		case key.Matches(msg, m.keys.Filter):
 			m.inputMode = ModeFilter
 			m.textInput.Placeholder = "Filter logs..."
 			m.textInput.SetValue(m.filterText)
 			m.textInput.SetCursor(len(m.filterText))
 			m.textInput.Focus()
+			m.resetHistoryBrowse()
 			return m, textinput.Blink
-		case "[":
+		case key.Matches(msg, m.keys.StartDate):
 			m.inputMode = ModeSetStartDate
 			m.textInput.Placeholder = "YYYY-MM-DD HH:MM:SS"
 			m.textInput.SetValue("") // Always clear for new date input? Or show existing?
@@ -482,8 +685,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.textInput.SetValue(m.startDate.Format("2006-01-02 15:04:05"))
 			}
 			m.textInput.Focus()
+			m.resetHistoryBrowse()
 			return m, textinput.Blink
-		case "]":
+		case key.Matches(msg, m.keys.EndDate):
 			m.inputMode = ModeSetEndDate
 			m.textInput.Placeholder = "YYYY-MM-DD HH:MM:SS"
 			if m.endDate != nil {
@@ -492,40 +696,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.textInput.SetValue("")
 			}
 			m.textInput.Focus()
+			m.resetHistoryBrowse()
 			return m, textinput.Blink
 
 		// Advanced Toggles
-		case "1":
+		case key.Matches(msg, m.keys.ToggleError):
 			m.showError = !m.showError
 			m.applyFilters(true)
-		case "2":
+		case key.Matches(msg, m.keys.ToggleWarn):
 			m.showWarn = !m.showWarn
 			m.applyFilters(true)
-		case "3":
+		case key.Matches(msg, m.keys.ToggleInfo):
 			m.showInfo = !m.showInfo
 			m.applyFilters(true)
-		case "4":
+		case key.Matches(msg, m.keys.ToggleDebug):
 			m.showDebug = !m.showDebug
 			m.applyFilters(true)
-		case "R":
+		case key.Matches(msg, m.keys.Regex):
 			m.regexMode = !m.regexMode
 			m.applyFilters(true) // Re-apply to update regex usage
 
 		// Horizontal Scrolling
-		case "right", "l":
+		case key.Matches(msg, m.keys.Right):
 			m.xOffset += 5
-		case "left", "h":
+		case key.Matches(msg, m.keys.Left):
 			m.xOffset -= 5
 			if m.xOffset < 0 {
 				m.xOffset = 0
 			}
 
 		// Toggle Word Wrap
-		case "w":
+		case key.Matches(msg, m.keys.Wrap):
 			m.wrap = !m.wrap
 
 		// Clear all filters
-		case "c":
+		case key.Matches(msg, m.keys.ClearFilters):
 			m.startDate = nil
 			m.endDate = nil
 			m.filterText = ""
@@ -533,22 +738,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.applyFilters(true)
 
 		// Toggle Follow Mode
-		case "f":
-			m.following = !m.following
-			if m.following {
-				m.yOffset = len(m.filteredLines) - m.viewport.Height
-				if m.yOffset < 0 {
-					m.yOffset = 0
-				}
-			}
+		case key.Matches(msg, m.keys.Follow):
+			m.setFollowing(!m.following)
 
 		// Toggle Stack Trace Folding
-		case "z":
+		case key.Matches(msg, m.keys.Fold):
 			m.foldStackTraces = !m.foldStackTraces
 			m.applyFilters(true)
 
+		case key.Matches(msg, m.keys.Undo):
+			m.undo()
+			m.skipUndoRecord = true
+			return m, nil
+		case key.Matches(msg, m.keys.Redo):
+			m.redo()
+			m.skipUndoRecord = true
+			return m, nil
+		case key.Matches(msg, m.keys.Redact):
+			m.toggleRedaction()
+			return m, nil
+		case key.Matches(msg, m.keys.LineNumbers):
+			m.toggleLineNumbers()
+			return m, nil
+		case key.Matches(msg, m.keys.SwitchPane):
+			m.focusOtherPane()
+			return m, nil
+		case key.Matches(msg, m.keys.Trace):
+			m.followTrace("")
+			return m, nil
+		case key.Matches(msg, m.keys.Alerts):
+			m.toggleAlerts()
+			return m, nil
+		case key.Matches(msg, m.keys.Restart):
+			return m, m.restartCommand()
+		case key.Matches(msg, m.keys.NextTab):
+			m.selectTab(m.activeTab + 1)
+			return m, nil
+		case key.Matches(msg, m.keys.PrevTab):
+			m.selectTab(m.activeTab - 1)
+			return m, nil
+
+		// Export the filtered view
+		case key.Matches(msg, m.keys.Export):
+			m.exportView()
+			return m, nil
+
+		// Toggle Repeat Collapsing
+		case key.Matches(msg, m.keys.Collapse):
+			m.collapseDuplicates = !m.collapseDuplicates
+			m.applyFilters(true)
+
+		// Detail Pane for the cursor line
+		case key.Matches(msg, m.keys.Detail):
+			m.showDetail = !m.showDetail
+			m.detailFocus = false
+			m.setCursor(m.cursor)
+
+		// Toggle Table View
+		case key.Matches(msg, m.keys.Table):
+			m.tableMode = !m.tableMode
+			if m.tableMode && len(m.tableColumns) == 0 {
+				m.tableColumns = detectColumns(m.filteredLines)
+			}
+			m.applyFilters(true)
+
 		// Toggle Timeline
-		case "t":
+		case key.Matches(msg, m.keys.Timeline):
 			m.showTimeline = !m.showTimeline
 			if m.showTimeline {
 				m.generateTimeline()
@@ -562,28 +817,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		// Time Travel
-		case "J":
+		case key.Matches(msg, m.keys.JumpTime):
 			m.inputMode = ModeJumpTime
 			m.textInput.Placeholder = "14:30 or YYYY-MM-DD..."
 			m.textInput.SetValue("")
 			m.textInput.Focus()
+			m.resetHistoryBrowse()
 			return m, textinput.Blink
 
 		// Bookmarks
-		case "m":
-			// Toggle bookmark at current YOffset (top visible line)
-			row := m.yOffset
+		case key.Matches(msg, m.keys.Bookmark):
+			// Toggle bookmark at the cursor line
+			row := m.cursor
 			if _, exists := m.bookmarks[row]; exists {
 				delete(m.bookmarks, row)
+				delete(m.bookmarkNotes, row)
 			} else {
 				m.bookmarks[row] = struct{}{}
 			}
 			// Invalidate cache for this line to ensure layout updates (e.g. bookmark icon vs gutter)
 			delete(m.layoutCache, row)
 
-		case "n":
-			// Jump to next bookmark > current YOffset
-			start := m.yOffset + 1
+		case key.Matches(msg, m.keys.NextBookmark):
+			// Jump to next bookmark > cursor line
+			start := m.cursor + 1
 			next := -1
 			minDist := int(^uint(0) >> 1)
 
@@ -598,12 +855,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			if next != -1 {
-				m.yOffset = next
+				m.setCursor(next)
 			}
 
-		case "N":
-			// Jump to prev bookmark < current YOffset
-			start := m.yOffset - 1
+		case key.Matches(msg, m.keys.PrevBookmark):
+			// Jump to prev bookmark < cursor line
+			start := m.cursor - 1
 			prev := -1
 			minDist := int(^uint(0) >> 1)
 
@@ -618,7 +875,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			if prev != -1 {
-				m.yOffset = prev
+				m.setCursor(prev)
 			}
 		}
 
@@ -626,20 +883,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
+		switch {
 		// Virtualized Scrolling
-		case "up", "k":
-			m.yOffset--
-		case "down", "j":
-			m.yOffset++
-		case "pgup", "ctrl+b":
-			m.yOffset -= m.viewport.Height
-		case "pgdown", "ctrl+f", "space":
-			m.yOffset += m.viewport.Height
-		case "home", "g":
+		case key.Matches(msg, m.keys.Up):
+			m.setCursor(m.cursor - 1)
+		case key.Matches(msg, m.keys.Down):
+			m.setCursor(m.cursor + 1)
+		case key.Matches(msg, m.keys.HalfPageUp):
+			m.yOffset -= m.pageHeight() / 2
+			m.setCursor(m.cursor - m.pageHeight()/2)
+		case key.Matches(msg, m.keys.HalfPageDown):
+			m.yOffset += m.pageHeight() / 2
+			m.setCursor(m.cursor + m.pageHeight()/2)
+		case key.Matches(msg, m.keys.PageUp):
+			m.yOffset -= m.pageHeight()
+		case key.Matches(msg, m.keys.PageDown):
+			m.yOffset += m.pageHeight()
+		case key.Matches(msg, m.keys.Top):
 			m.yOffset = 0
-		case "end", "G":
-			m.yOffset = len(m.filteredLines) - m.viewport.Height
+			m.setCursor(0)
+		case key.Matches(msg, m.keys.Bottom):
+			m.yOffset = len(m.filteredLines) - m.pageHeight()
+			m.setCursor(len(m.filteredLines) - 1)
 		}
 	case tea.MouseMsg:
 		switch msg.Button {
@@ -672,7 +937,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.yOffset < 0 {
 		m.yOffset = 0
 	}
-	maxOffset := len(m.filteredLines) - m.viewport.Height
+	maxOffset := len(m.filteredLines) - m.pageHeight()
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -680,10 +945,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.yOffset = maxOffset
 	}
 
+	// Keep the cursor line on screen
+	if h := m.pageHeight(); m.cursor < m.yOffset || (h > 0 && m.cursor >= m.yOffset+h) {
+		m.setCursor(max(m.yOffset, min(m.cursor, m.yOffset+h-1)))
+	}
+
 	// Disable follow mode if user scrolls up manually
 	// (Simple heuristic: if not at bottom)
-	if m.yOffset < maxOffset {
-		m.following = false
+	if m.following && m.yOffset < maxOffset {
+		m.setFollowing(false)
 	}
 
 	// We manage mouse scrolling via m.yOffset. Passing mouse events to viewport
@@ -710,21 +980,34 @@ func (m *Model) canFastAppendWithoutRefilter() bool {
 		m.showWarn &&
 		m.showInfo &&
 		m.showDebug &&
-		!m.foldStackTraces
+		!m.foldStackTraces &&
+		!m.collapseDuplicates &&
+		!(m.tableMode && m.tableSortCol != "")
 }
 
 func (m *Model) appendIncomingLines(newLines []string) {
 	if len(newLines) == 0 {
 		return
 	}
+	m.store.lines = append(m.store.lines, newLines...)
+	m.showAppendedLines(newLines)
+}
 
-	m.originalLines = append(m.originalLines, newLines...)
+// showAppendedLines extends the view with lines just added to the store.
+func (m *Model) showAppendedLines(newLines []string) {
+	m.originalLines = m.store.lines
 
 	if m.canFastAppendWithoutRefilter() {
-		for _, line := range newLines {
+		base := len(m.originalLines) - len(newLines)
+		for i, line := range newLines {
 			line = strings.ReplaceAll(line, "\t", "    ")
+			if m.redact {
+				line = redactLine(line)
+			}
 			m.filteredLines = append(m.filteredLines, line)
+			m.filteredRefs = append(m.filteredRefs, lineRef{first: base + i, last: base + i})
 		}
+		m.countLevels()
 		return
 	}
 
@@ -743,6 +1026,7 @@ func splitIncomingContent(content string) []string {
 
 func (m *Model) applyFilters(resetView bool) {
 	var filtered []string
+	var refs []lineRef
 	// Directly iterate over originalLines
 	lines := m.originalLines
 
@@ -761,32 +1045,43 @@ func (m *Model) applyFilters(resetView bool) {
 		m.regex = nil
 	}
 
-	for _, line := range lines {
+	// The time index narrows a date filter to the lines that can match.
+	var times *timeIndex
+	lo, hi := 0, len(lines)
+	if m.startDate != nil || m.endDate != nil {
+		times = m.timeIndex()
+		lo, hi = times.span(m.startDate, m.endDate)
+	}
+
+	for idx := 0; idx < len(lines); idx++ {
+		line := lines[idx]
+		if times != nil {
+			if idx >= hi {
+				break
+			}
+			if idx >= times.first && idx < lo {
+				idx = lo - 1
+				continue
+			}
+		}
+
 		// 1. Level Filtering
-		if strings.Contains(line, "ERROR") && !m.showError {
+		if !m.showError && hasLevel(line, levelError) {
 			continue
 		}
-		if strings.Contains(line, "WARN") && !m.showWarn {
+		if !m.showWarn && hasLevel(line, levelWarn) {
 			continue
 		}
-		if strings.Contains(line, "INFO") && !m.showInfo {
+		if !m.showInfo && hasLevel(line, levelInfo) {
 			continue
 		}
-		if strings.Contains(line, "DEBUG") && !m.showDebug {
+		if !m.showDebug && hasLevel(line, levelDebug) {
 			continue
 		}
 
-		// 2. Date Filtering
-		if m.startDate != nil || m.endDate != nil {
-			t, ok := extractDate(line)
-			if ok {
-				if m.startDate != nil && t.Before(*m.startDate) {
-					continue
-				}
-				if m.endDate != nil && t.After(*m.endDate) {
-					continue
-				}
-			}
+		// 2. Date Filtering (continuation lines go with their record)
+		if times != nil && !times.inRange(idx, m.startDate, m.endDate) {
+			continue
 		}
 
 		// 3. Text/Regex Filtering
@@ -805,8 +1100,12 @@ func (m *Model) applyFilters(resetView bool) {
 
 		// Tab Normalization (Fixes offset drift in selection)
 		line = strings.ReplaceAll(line, "\t", "    ")
+		if m.redact {
+			line = redactLine(line)
+		}
 
 		filtered = append(filtered, line)
+		refs = append(refs, lineRef{first: idx, last: idx})
 	}
 
 	// Stack Trace Folding Logic
@@ -814,41 +1113,60 @@ func (m *Model) applyFilters(resetView bool) {
 	// If folding, we process the 'filtered' list again (or ideally during initial pass, but separation is cleaner for MVP).
 	if m.foldStackTraces {
 		var folded []string
+		var foldedRefs []lineRef
 		var traceBuffer []string
+		var traceRefs []lineRef
 
 		flushTrace := func() {
 			if len(traceBuffer) > 0 {
 				// Heuristic: If just 1 line, don't fold.
 				if len(traceBuffer) == 1 {
 					folded = append(folded, traceBuffer...)
+					foldedRefs = append(foldedRefs, traceRefs...)
 				} else {
 					// Fold!
 					summary := fmt.Sprintf("  [+] %d lines folded (stack trace/indented block)...", len(traceBuffer))
-					// Style it?
-					summary = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true).Render(summary)
+					summary = foldSummaryStyle.Render(summary)
 					folded = append(folded, summary)
+					foldedRefs = append(foldedRefs, lineRef{first: traceRefs[0].first, last: traceRefs[len(traceRefs)-1].last})
 				}
 				traceBuffer = nil
+				traceRefs = nil
 			}
 		}
 
-		for _, line := range filtered {
+		for i, line := range filtered {
 			// Check for indentation (heuristic for stack trace)
 			// TAB or at least 2 spaces
 			isIndented := strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "  ")
 
 			if isIndented {
 				traceBuffer = append(traceBuffer, line)
+				traceRefs = append(traceRefs, refs[i])
 			} else {
 				flushTrace()
 				folded = append(folded, line)
+				foldedRefs = append(foldedRefs, refs[i])
 			}
 		}
 		flushTrace()
 		m.filteredLines = folded
+		m.filteredRefs = foldedRefs
 	} else {
 		m.filteredLines = filtered
+		m.filteredRefs = refs
+	}
+
+	// Collapse runs of near-identical lines (retry loops, health checks...)
+	if m.collapseDuplicates {
+		m.filteredLines, m.filteredRefs = collapseRepeats(m.filteredLines, m.filteredRefs)
+	}
+
+	if m.tableMode && m.tableSortCol != "" {
+		m.filteredLines, m.filteredRefs = sortByColumn(m.filteredLines, m.filteredRefs, m.tableSortCol, m.tableSortDesc)
 	}
+	m.levelCounts, m.levelsCounted = [4]int{}, 0
+	m.countLevels()
 
 	if resetView {
 		// Clear selection on filter change
@@ -856,9 +1174,15 @@ func (m *Model) applyFilters(resetView bool) {
 		m.selectionEnd = nil
 		// Clear bookmarks on filter change? indices are invalid.
 		m.bookmarks = make(map[int]struct{})
+		m.bookmarkNotes = make(map[int]string)
 
 		// Virtualization reset
 		m.yOffset = 0
+		m.cursor = 0
+		m.cursorX = 0
+		m.visualMode = visualNone
+	} else if m.cursor >= len(m.filteredLines) {
+		m.cursor = max(0, len(m.filteredLines)-1)
 	}
 	// Always clear viewport content as View() reconstructs it
 	m.viewport.SetContent("")
@@ -870,11 +1194,24 @@ func (m *Model) applyFilters(resetView bool) {
 }
 
 func (m Model) View() string {
+	view := m.screenView()
+	if m.alerts.ringing {
+		// First, where a full-width line cannot truncate it away
+		view = "\a" + view
+	}
+	if m.osc52 != "" {
+		view = m.osc52 + view
+	}
+	return view
+}
+
+// screenView renders the window: the log view, or the help or palette over it.
+func (m Model) screenView() string {
 	if !m.ready {
 		return "\n  Initializing..."
 	}
 
-	if m.showHelp {
+	if m.showHelp || m.inputMode == ModePalette {
 		// Calculate total height
 		height := m.viewport.Height + m.headerHeight + m.footerHeight
 		if height == 0 {
@@ -885,16 +1222,32 @@ func (m Model) View() string {
 			width = 80
 		}
 
+		overlay := m.helpView()
+		if m.inputMode == ModePalette {
+			overlay = m.paletteView()
+		}
 		return lipgloss.Place(width, height,
 			lipgloss.Center, lipgloss.Center,
-			m.helpView(),
+			overlay,
 		)
 	}
 
+	body := m.bodyView()
+	if len(m.split) > 0 {
+		body = m.splitView(body)
+	}
+	return fmt.Sprintf("%s\n%s\n%s", m.headerView(), body, m.footerView())
+}
+
+// bodyView renders the visible log lines (or the timeline) of this view.
+func (m Model) bodyView() string {
+	if m.showAlerts {
+		return m.alertsView()
+	}
 	// Virtualization:
 	// 1. Determine visible slice from m.filteredLines based on m.yOffset
 	start := m.yOffset
-	end := start + m.viewport.Height
+	end := start + m.pageHeight()
 	if start >= len(m.filteredLines) {
 		start = len(m.filteredLines)
 	}
@@ -906,13 +1259,21 @@ func (m Model) View() string {
 
 	// 2. Iterate and apply highlighting/selection to only these lines
 	var renderedLines []string
+
+	// Table mode sizes columns to the visible rows and pins a header row.
+	var tableWidths []int
+	if m.tableMode {
+		tableWidths = tableColumnWidths(m.tableColumns, visibleLines)
+		renderedLines = append(renderedLines, m.tableHeaderView(tableWidths))
+	}
+
 	for i, line := range visibleLines {
 		// Calculate real line index
 		realLineIndex := start + i
 
-		isBookmarked := false
-		if _, ok := m.bookmarks[realLineIndex]; ok {
-			isBookmarked = true
+		if m.tableMode {
+			renderedLines = append(renderedLines, m.tableRowView(realLineIndex, line, tableWidths))
+			continue
 		}
 
 		// 2. Wrap vs Horizontal Scroll
@@ -1011,11 +1372,6 @@ func (m Model) View() string {
 					width = 80
 				}
 
-				renderedLine := line // Start with raw
-
-				// Apply decorations (Regex, Level)
-				renderedLine = m.getDecoratedLine(realLineIndex, renderedLine)
-
 				// Cache Key: We use index. If content changes, filter clears cache.
 				// If selection exists, we might normally bypass cache or modify key.
 				// But simplest fix for "scrolling is slow":
@@ -1037,8 +1393,6 @@ func (m Model) View() string {
 
 				// To minimize code drift, let's just Wrap and cache if !lineSelected.
 
-				// Re-running generation:
-				line = m.getDecoratedLine(realLineIndex, line)
 				if lineSelected {
 					// Apply selection logic (same as before)
 					startSel, endSel := *m.selectionStart, *m.selectionEnd
@@ -1078,7 +1432,8 @@ func (m Model) View() string {
 					}
 				}
 
-				wrapped = lipgloss.NewStyle().Width(width).Render(line)
+				// The gutter goes on once, after decoration and selection.
+				wrapped = lipgloss.NewStyle().Width(width).Render(m.lineNumber(realLineIndex) + line + m.repeatBadge(realLineIndex))
 
 				// Store in cache only if NOT selected (or if selected? selection changes often)
 				// If we cache selected state, dragging execution is slow?
@@ -1091,6 +1446,15 @@ func (m Model) View() string {
 				}
 			}
 
+			// Cursor line: reverse video keeps the wrapped shape intact.
+			if realLineIndex == m.cursor {
+				parts := strings.Split(wrapped, "\n")
+				for k, part := range parts {
+					parts[k] = cursorLineStyle.Render(stripAnsi(part))
+				}
+				wrapped = strings.Join(parts, "\n")
+			}
+
 			renderedLines = append(renderedLines, wrapped)
 
 		} else {
@@ -1110,7 +1474,8 @@ func (m Model) View() string {
 
 				// Highlight visible part
 				visiblePart = highlightMatches(visiblePart, m.regex)
-				line = highlightLine(visiblePart)
+				from := len(string(rawRunes[:m.xOffset]))
+				line = m.tintStderr(realLineIndex, m.applyHighlightRulesSlice(highlightLine(visiblePart), rawLine, from))
 
 				// 2. Selection Highlighting (Lazy)
 				if m.selectionStart != nil && m.selectionEnd != nil {
@@ -1156,11 +1521,10 @@ func (m Model) View() string {
 					}
 				}
 
-				// 3. Apply Bookmark (Visual Only, after highlighting/selection)
-				if isBookmarked {
-					line = "🔖 " + line
-				} else {
-					line = "   " + line // Maintain alignment
+				// 3. Apply Bookmark / Cursor Gutter (Visual Only, after highlighting/selection)
+				line = m.gutter(realLineIndex) + line
+				if end == len(rawRunes) {
+					line += m.repeatBadge(realLineIndex)
 				}
 
 			} else {
@@ -1175,17 +1539,28 @@ func (m Model) View() string {
 	// Join rendered lines
 	finalContent := strings.Join(renderedLines, "\n")
 
+	// Split the viewport between the list and the detail pane.
+	if m.showDetail {
+		listHeight := m.viewport.Height - m.detailHeight()
+		listLines := strings.Split(finalContent, "\n")
+		if len(listLines) > listHeight {
+			listLines = listLines[:listHeight]
+		}
+		for len(listLines) < listHeight {
+			listLines = append(listLines, "")
+		}
+		finalContent = strings.Join(listLines, "\n") + "\n" + m.detailView()
+	}
+
 	// IMPORTANT: Feed the rendered (and potentially wrapped) content to the viewport
 	// This handling clipping (ensure we don't exceed height) and padding if strictly needed.
 	m.viewport.SetContent(finalContent)
 	m.viewport.YOffset = 0
 
-	currentView := m.viewport.View()
 	if m.showTimeline {
-		currentView = m.timelineViewport.View()
+		return m.timelineViewport.View()
 	}
-
-	return fmt.Sprintf("%s\n%s\n%s", m.headerView(), currentView, m.footerView())
+	return m.viewport.View()
 }
 
 func (m *Model) generateTimeline() {
@@ -1289,14 +1664,10 @@ func highlightLine(line string) string {
 		}
 	}
 
-	if strings.Contains(line, "ERROR") {
-		return strings.Replace(line, "ERROR", errorStyle.Render("ERROR"), 1)
-	} else if strings.Contains(line, "WARN") {
-		return strings.Replace(line, "WARN", warnStyle.Render("WARN"), 1)
-	} else if strings.Contains(line, "INFO") {
-		return strings.Replace(line, "INFO", infoStyleLog.Render("INFO"), 1)
-	} else if strings.Contains(line, "DEBUG") {
-		return strings.Replace(line, "DEBUG", debugStyle.Render("DEBUG"), 1)
+	for _, lvl := range []logLevel{levelError, levelWarn, levelInfo, levelDebug} {
+		if kw, ok := levelKeyword(line, lvl); ok {
+			return strings.Replace(line, kw, levelStyle(lvl).Render(kw), 1)
+		}
 	}
 	return line
 }
@@ -1325,7 +1696,7 @@ func parseDate(s string) (time.Time, error) {
 		time.RFC3339,
 	}
 	for _, f := range formats {
-		t, err := time.Parse(f, s)
+		t, err := time.ParseInLocation(f, s, timeLocation)
 		if err == nil {
 			return t, nil
 		}
@@ -1333,6 +1704,48 @@ func parseDate(s string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("unknown format")
 }
 
+// parseTargetTime parses a full date, or a bare clock time (14:30) taken on
+// the day of the first visible line.
+func (m Model) parseTargetTime(val string) (time.Time, error) {
+	target, err := parseDate(val)
+	// Heuristic: If parsing fails or assumes year 0, try combining with first log line date
+	if err != nil || target.Year() == 0 {
+		// Try to interpret as HH:MM or HH:MM:SS relative to first log line
+		// Get base date
+		if len(m.filteredLines) > 0 {
+			// Simple: split first line
+			firstLine := m.filteredLines[0]
+			if base, ok := extractDate(firstLine); ok {
+				// Try to parse val as HH:MM:SS
+				// We can use a custom parser or try strict formats
+				// Simple approach: Replace timestamp in base with val?
+				// Or parse val as time.Time (0000-01-01 HH:MM partial) and join.
+
+				// Let's rely on time.Parse for just time formats
+				timeFormats := []string{"15:04", "15:04:05", "3:04PM"}
+				var timeComponent time.Time
+				parsedTime := false
+				for _, tf := range timeFormats {
+					if tc, err := time.Parse(tf, val); err == nil {
+						timeComponent = tc
+						parsedTime = true
+						break
+					}
+				}
+
+				if parsedTime {
+					// Combine base YYYY-MM-DD with timeComponent HH:MM:SS
+					year, month, day := base.Date()
+					hour, min, sec := timeComponent.Clock()
+					target = time.Date(year, month, day, hour, min, sec, 0, base.Location())
+					err = nil // Success
+				}
+			}
+		}
+	}
+	return target, err
+}
+
 func extractDate(line string) (time.Time, bool) {
 	// Simple heuristic: look for the first occurrence of something looking like a date
 	// 2023-01-01 or 2023-01-01T...
@@ -1362,6 +1775,9 @@ var jsonRegex = regexp.MustCompile(`"([^"]+)":`)
 
 func (m Model) headerView() string {
 	title := titleStyle.Render(m.filename)
+	if len(m.tabs) > 1 {
+		title = m.tabBarView()
+	}
 	line := strings.Repeat("─", max(0, m.viewport.Width-lipgloss.Width(title)))
 	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
 }
@@ -1379,6 +1795,15 @@ func (m Model) footerView() string {
 			prefix = "[End]: "
 		case ModeJumpTime:
 			prefix = "[Jump To]: "
+		case ModeTableColumns:
+			prefix = "[Columns]: "
+		case ModeCommand:
+			prefix = ":"
+		case ModePalette:
+			return mutedStyle.Render("↑/↓ select • enter run • esc close")
+		}
+		if m.historySearching {
+			return prefix + m.historySearchView()
 		}
 		return prefix + m.textInput.View()
 	}
@@ -1387,7 +1812,7 @@ func (m Model) footerView() string {
 	// Calculate scroll percent manually
 	var percent float64
 	if len(m.filteredLines) > 0 {
-		percent = float64(m.yOffset) / float64(len(m.filteredLines)-m.viewport.Height)
+		percent = float64(m.yOffset) / float64(len(m.filteredLines)-m.pageHeight())
 		if percent < 0 {
 			percent = 0
 		}
@@ -1408,17 +1833,48 @@ func (m Model) footerView() string {
 		status += fmt.Sprintf("│ End: %s ", m.endDate.Format("15:04"))
 	}
 
+	if m.collapseDuplicates {
+		status += "│ DEDUP "
+	}
+
+	if m.redact {
+		status += "│ REDACTED "
+	}
+
+	if m.alerts.unseen > 0 {
+		status += fmt.Sprintf("│ ⚠ %d ", m.alerts.unseen)
+	}
+
+	if m.tableMode {
+		status += "│ TABLE "
+	}
+
+	status = footerStyle.Render(status)
+
 	if m.following {
 		// Blinking indicator? Or just bold color?
-		status += "│ " + infoStyle.Render("LIVE")
+		status += footerStyle.Render("│ ") + infoStyle.Render("LIVE") + " "
 	}
+	status += m.pauseView() + footerStyle.Render(m.rateView()) + m.levelCountsView() + m.sourceView() + m.processView()
 
-	// Right aligned help hint
+	// Right aligned help hint (or the latest status message)
 	help := " ? Help "
+	if m.count != "" {
+		help = " " + m.count + " "
+	} else if m.statusMsg != "" {
+		help = " " + m.statusMsg + " "
+	} else if note, ok := m.bookmarkNotes[m.cursor]; ok {
+		help = " 🔖 " + note + " "
+	}
 
 	// Assemble
 	totalWidth := m.viewport.Width
 	leftSide := status
+	if m.alerts.flashing {
+		help = errorStyle.Reverse(true).Render(help)
+	} else {
+		help = footerStyle.Render(help)
+	}
 
 	// Spacer
 	spaceCount := max(0, totalWidth-lipgloss.Width(leftSide)-lipgloss.Width(help))
@@ -1427,6 +1883,15 @@ func (m Model) footerView() string {
 	return lipgloss.JoinHorizontal(lipgloss.Center, leftSide, line, help)
 }
 
+// pageHeight is the number of log lines that fit in the viewport.
+func (m Model) pageHeight() int {
+	h := m.viewport.Height - m.detailHeight()
+	if m.tableMode {
+		h-- // Header row
+	}
+	return max(0, h)
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -1435,78 +1900,61 @@ func max(a, b int) int {
 }
 
 func (m Model) helpView() string {
-	// Define helper struct for keys
-	type helpEntry struct {
-		key, desc string
-	}
-
-	// Group keys
-	general := []helpEntry{
-		{"?", "Close Help"},
-		{"q / esc", "Quit / Close"},
-		{"ctrl+c", "Quit"},
-	}
-
-	nav := []helpEntry{
-		{"j / k", "Scroll Down / Up"},
-		{"g / G", "Scroll Top / Bottom"},
-		{"f", "Toggle Follow"},
-		{"J", "Jump to Time"},
-		{"space", "Page Down"},
-		{"b / u", "PgUp / PgDown"},
-	}
-
-	filtering := []helpEntry{
-		{"/", "Filter Logs"},
-		{"c", "Clear Filters"},
-		{"R", "Regex Toggle"},
-		{"s / e", "Set Start / End (Time)"},
-		{"1-4", "Toggle Levels (Err/Warn...)"},
-	}
-
-	viewing := []helpEntry{
-		{"w", "Toggle Wrap"},
-		{"z", "Fold Stack Traces"},
-		{"t", "Toggle Timeline"},
-		{"m", "Toggle Bookmark"},
-		{"l / h", "Scroll Right / Left"},
-		{"Shift+Wheel", "Scroll Right / Left"},
-		{"r", "Reload File"},
+	// Group bindings by help section, keeping keymap order
+	groups := make(map[string][]keyAction)
+	for _, a := range m.keys.actions() {
+		if a.binding.Enabled() {
+			groups[a.group] = append(groups[a.group], a)
+		}
 	}
 
 	// Styles
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(titleStyle.GetBorderTopForeground()).
 		Padding(1, 2).
 		Margin(1)
 
-	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+	headerStyle := mutedStyle.
 		Bold(true).
 		Underline(true).
 		MarginBottom(1)
 
-	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	keyStyle := helpKeyStyle
+	descStyle := footerStyle
 
 	// Helper to render a column
-	renderColumn := func(title string, entries []helpEntry) string {
-		s := headerStyle.Render(title) + "\n"
-		for _, e := range entries {
-			k := keyStyle.Width(10).Render(e.key)
-			d := descStyle.Render(e.desc)
-			s += fmt.Sprintf("%s %s\n", k, d)
+	renderColumn := func(titles ...string) string {
+		width := 0
+		for _, title := range titles {
+			for _, a := range groups[title] {
+				width = max(width, lipgloss.Width(helpKeys(*a.binding)))
+			}
+		}
+		var parts []string
+		for _, title := range titles {
+			s := headerStyle.Render(title) + "\n"
+			for _, a := range groups[title] {
+				k := keyStyle.Width(width).Render(helpKeys(*a.binding))
+				d := descStyle.Render(a.binding.Help().Desc)
+				s += fmt.Sprintf("%s %s\n", k, d)
+			}
+			parts = append(parts, s)
 		}
-		return s
+		return strings.Join(parts, "\n")
 	}
 
 	// Layout
-	col1 := renderColumn("General", general) + "\n" + renderColumn("Navigation", nav)
-	col2 := renderColumn("Filtering", filtering) + "\n" + renderColumn("View & Tools", viewing)
-
-	// Join columns with gap
-	content := lipgloss.JoinHorizontal(lipgloss.Top, col1, "    ", col2)
+	col1 := renderColumn(keyGroupGeneral, keyGroupNavigation)
+	col2 := renderColumn(keyGroupFiltering, keyGroupView)
+	col3 := renderColumn(modeGroups...)
+
+	// Join columns with gap; the mode keys go below when they do not fit
+	content := lipgloss.JoinHorizontal(lipgloss.Top, col1, "    ", col2, "    ", col3)
+	if m.screenWidth > 0 && lipgloss.Width(content)+boxStyle.GetHorizontalFrameSize() > m.screenWidth {
+		col1 = lipgloss.JoinVertical(lipgloss.Left, col1, "", col3)
+		content = lipgloss.JoinHorizontal(lipgloss.Top, col1, "    ", col2)
+	}
 
 	return boxStyle.Render(content)
 }
@@ -1559,7 +2007,7 @@ func (m *Model) copySelection() {
 		}
 
 		text := strings.Join(selectedLines, "\n")
-		clipboard.WriteAll(text)
+		m.copyToClipboard(text)
 
 		// Clear selection after copy?
 		// User preference: might want to keep selection?
@@ -1572,6 +2020,7 @@ func (m *Model) copySelection() {
 func (m Model) getDecoratedLine(i int, line string) string {
 	line = highlightMatches(line, m.regex)
 	line = highlightLine(line)
+	line = m.tintStderr(i, m.applyHighlightRules(line))
 	if _, ok := m.bookmarks[i]; ok {
 		line = "🔖 " + line
 	}
@@ -1582,7 +2031,10 @@ func (m Model) resolvePos(visualX, visualY int) (int, int) {
 	if !m.wrap {
 		// Default behavior (No Wrap)
 		logicalLine := m.yOffset + visualY
-		gutterOffset := 3
+		if m.tableMode {
+			logicalLine-- // Header row
+		}
+		gutterOffset := m.gutterWidth()
 		logicalX := m.xOffset + visualX - gutterOffset
 		if logicalX < 0 {
 			logicalX = 0
@@ -1620,6 +2072,7 @@ func (m Model) resolvePos(visualX, visualY int) (int, int) {
 			if _, ok := m.bookmarks[idx]; ok {
 				plain = "🔖 " + plain
 			}
+			plain = stripAnsi(m.lineNumber(idx)) + plain + stripAnsi(m.repeatBadge(idx))
 
 			// Wrap plain text
 			wrapped := lipgloss.NewStyle().Width(width).Render(plain)
@@ -1638,6 +2091,7 @@ func (m Model) resolvePos(visualX, visualY int) (int, int) {
 				if _, ok := m.bookmarks[idx]; ok {
 					plain = "🔖 " + plain
 				}
+				plain = stripAnsi(m.lineNumber(idx)) + plain + stripAnsi(m.repeatBadge(idx))
 			}
 
 			// Reconstruct offset by matching parts against original plain line
@@ -1710,7 +2164,7 @@ func (m Model) resolvePos(visualX, visualY int) (int, int) {
 			// Bookmark is "🔖 " -> Rune count: 2 (Bookmark char + space).
 			// We want index into the LOG LINE (without bookmark).
 
-			finalIdx := startOfLineRuneIdx + foundIdx
+			finalIdx := startOfLineRuneIdx + foundIdx - m.lineNumberWidth()
 
 			if _, ok := m.bookmarks[idx]; ok {
 				// Original plain was "🔖 " + content
@@ -1723,7 +2177,7 @@ func (m Model) resolvePos(visualX, visualY int) (int, int) {
 				}
 			}
 
-			targetCharIndex = finalIdx
+			targetCharIndex = max(0, finalIdx)
 			return targetLineIndex, targetCharIndex
 		}
 
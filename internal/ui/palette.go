@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxPaletteRows = 15
+
+// paletteEntry is one action in the palette.
+type paletteEntry struct {
+	action keyAction
+	score  int
+}
+
+// namedKeys maps Bubble Tea key names ("enter", "ctrl+s", "pgup"…) back to
+// key types so palette entries can replay their binding.
+var namedKeys = func() map[string]tea.KeyType {
+	names := make(map[string]tea.KeyType)
+	for t := tea.KeyType(-100); t < 128; t++ {
+		if name := t.String(); name != "" {
+			if _, dup := names[name]; !dup {
+				names[name] = t
+			}
+		}
+	}
+	return names
+}()
+
+// keyMsgFromString builds the key message whose String() is s.
+func keyMsgFromString(s string) tea.KeyMsg {
+	alt := false
+	if rest, ok := strings.CutPrefix(s, "alt+"); ok && len(rest) > 0 {
+		alt, s = true, rest
+	}
+	if t, ok := namedKeys[s]; ok {
+		return tea.KeyMsg{Type: t, Alt: alt}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s), Alt: alt}
+}
+
+// fuzzyScore matches query as a case-insensitive subsequence of text.
+// Consecutive and word-start matches score higher.
+func fuzzyScore(query, text string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	score, qi, prev := 0, 0, -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 2
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) {
+			score += 3
+		}
+		prev = ti
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+// openPalette shows the action palette.
+func (m *Model) openPalette() tea.Cmd {
+	m.inputMode = ModePalette
+	m.paletteCursor = 0
+	m.textInput.Placeholder = "Type to search actions…"
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	return nil
+}
+
+// paletteEntries lists the normal-mode actions matching the palette query,
+// best first.
+func (m Model) paletteEntries() []paletteEntry {
+	query := m.textInput.Value()
+	var entries []paletteEntry
+	for _, a := range m.keys.actions() {
+		if a.mode() != "" || a.name == "percent_jump" {
+			continue // Mode keys, and % which only acts on a count
+		}
+		text := a.binding.Help().Desc + " " + a.name
+		if score, ok := fuzzyScore(query, text); ok {
+			entries = append(entries, paletteEntry{action: a, score: score})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].score > entries[j].score })
+	return entries
+}
+
+// updatePalette handles keys while the palette is open.
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.paletteEntries()
+	switch msg.String() {
+	case "esc":
+		m.inputMode = ModeNormal
+		m.textInput.Blur()
+		return m, nil
+	case "up", "ctrl+p", "ctrl+k":
+		m.paletteCursor = max(0, m.paletteCursor-1)
+		return m, nil
+	case "down", "ctrl+n", "ctrl+j":
+		m.paletteCursor = max(0, min(len(entries)-1, m.paletteCursor+1))
+		return m, nil
+	case "enter":
+		m.inputMode = ModeNormal
+		m.textInput.Blur()
+		if len(entries) == 0 || m.paletteCursor >= len(entries) {
+			return m, nil
+		}
+		b := entries[m.paletteCursor].action.binding
+		if !b.Enabled() || len(b.Keys()) == 0 {
+			m.statusMsg = "Action has no key binding"
+			return m, nil
+		}
+		return m.update(keyMsgFromString(b.Keys()[0]))
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	m.paletteCursor = 0
+	return m, cmd
+}
+
+// paletteView renders the palette box: each action with its keys.
+func (m Model) paletteView() string {
+	entries := m.paletteEntries()
+	start := max(0, m.paletteCursor-maxPaletteRows+1)
+
+	descWidth := 0
+	for _, e := range entries {
+		descWidth = max(descWidth, lipgloss.Width(e.action.binding.Help().Desc))
+	}
+
+	rows := []string{"> " + m.textInput.View(), ""}
+	for i := start; i < len(entries) && i < start+maxPaletteRows; i++ {
+		e := entries[i]
+		keys := helpKeys(*e.action.binding)
+		if !e.action.binding.Enabled() {
+			keys = "unbound"
+		}
+		row := lipgloss.NewStyle().Width(descWidth).Render(e.action.binding.Help().Desc) + "  " + helpKeyStyle.Render(keys)
+		if i == m.paletteCursor {
+			row = selectedStyle.Render(stripAnsi(row))
+		}
+		rows = append(rows, row)
+	}
+	if len(entries) == 0 {
+		rows = append(rows, mutedStyle.Render("No matching actions"))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(titleStyle.GetBorderTopForeground()).
+		Padding(0, 1).
+		Render(strings.Join(rows, "\n"))
+}
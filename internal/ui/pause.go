@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// setFollowing turns follow mode on, catching up with the lines buffered
+// while paused, or off, which pauses the view: it stays as it is while new
+// lines are buffered in the store.
+func (m *Model) setFollowing(on bool) {
+	m.following = on
+	m.paused = !on
+	if on {
+		m.syncStore()
+		m.yOffset = max(0, len(m.filteredLines)-m.pageHeight())
+		if len(m.filteredLines) > 0 {
+			m.setCursor(len(m.filteredLines) - 1)
+		}
+	}
+}
+
+// pending is the number of lines the store received since the view paused.
+func (m Model) pending() int {
+	return len(m.store.lines) - len(m.originalLines)
+}
+
+// pauseView shows a paused view and how many new lines wait behind it.
+func (m Model) pauseView() string {
+	if !m.paused {
+		return ""
+	}
+	view := footerStyle.Render("│ ") + warnStyle.Render("PAUSED")
+	if n := m.pending(); n > 0 {
+		view += footerStyle.Render(" +" + formatCount(n) + " new")
+	}
+	return view + " "
+}
+
+// formatCount writes n with thousands separators.
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// hold stops reading s until releaseHeld, telling the panes showing it.
+func (m *Model) hold(s *lineStore) {
+	for _, p := range m.panes() {
+		if p.store == s {
+			m.statusMsg = fmt.Sprintf("Paused with %s new lines; reading stops until you resume", formatCount(p.pending()))
+		}
+	}
+}
+
+// holding reports whether reading s should wait: every view of it is paused
+// and one of them has buffered the configured limit.
+func (m *Model) holding(s *lineStore) bool {
+	limit := m.cfg.Stream.PauseBuffer
+	if limit <= 0 {
+		return false
+	}
+	full := false
+	for _, v := range m.views() {
+		if v.store != s {
+			continue
+		}
+		if !v.paused {
+			return false
+		}
+		full = full || v.pending() >= limit
+	}
+	return full
+}
+
+// releaseHeld resumes reading the sources held by paused views once they no
+// longer need to be.
+func (m *Model) releaseHeld() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, s := range m.stores() {
+		if (len(s.heldStreams) == 0 && !s.heldWatch) || m.holding(s) {
+			continue
+		}
+		for _, st := range s.heldStreams {
+			cmds = append(cmds, WaitForStream(st))
+		}
+		s.heldStreams = nil
+		if s.heldWatch {
+			// Read what was written while held before waiting for changes.
+			s.heldWatch = false
+			name, offset := s.name, s.fileSize
+			cmds = append(cmds, func() tea.Msg { return readNewContent(name, offset) })
+		}
+	}
+	return tea.Batch(cmds...)
+}
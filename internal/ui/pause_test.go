@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPauseBuffersNewLines(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	m := resize(InitialModel("test.log", numberedLines(50), r), 80, 24)
+	m.cfg.Stream.PauseBuffer = 3
+	m = pressKeys(m, "f")
+	if !m.following {
+		t.Fatal("Expected f to follow")
+	}
+
+	m = pressKeys(m, "b")
+	if m.following || !m.paused {
+		t.Fatal("Expected scrolling up to pause")
+	}
+	top := m.yOffset
+
+	updated, cmd := m.Update(LogChunkMsg{Lines: []string{"INFO a", "INFO b"}})
+	m = updated.(Model)
+	if len(m.filteredLines) != 50 || m.yOffset != top {
+		t.Fatalf("Expected the paused view to stay as it was, got %d lines at %d", len(m.filteredLines), m.yOffset)
+	}
+	if !strings.Contains(stripAnsi(m.footerView()), "PAUSED +2 new") {
+		t.Errorf("Expected the footer to count new lines, got %q", stripAnsi(m.footerView()))
+	}
+	if cmd == nil || len(m.store.heldStreams) > 0 {
+		t.Error("Expected reading to go on under the limit")
+	}
+
+	updated, _ = m.Update(LogChunkMsg{Lines: []string{"INFO c"}})
+	m = updated.(Model)
+	if len(m.store.heldStreams) == 0 {
+		t.Fatal("Expected reading to stop at the buffer limit")
+	}
+
+	m = pressKeys(m, "f")
+	if !m.following || m.paused || len(m.store.heldStreams) > 0 {
+		t.Fatal("Expected f to resume and release the source")
+	}
+	if len(m.filteredLines) != 53 || m.filteredLines[m.cursor] != "INFO c" {
+		t.Errorf("Expected to jump to the newest line, got %d lines, cursor on %q", len(m.filteredLines), m.filteredLines[m.cursor])
+	}
+	if strings.Contains(stripAnsi(m.footerView()), "PAUSED") {
+		t.Error("Expected the pause indicator to go")
+	}
+}
+
+func TestSetFollowResumes(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	m := resize(InitialModel("test.log", numberedLines(50), r), 80, 24)
+	m = pressKeys(m, "f", "b")
+	updated, _ := m.Update(LogChunkMsg{Lines: []string{"INFO a"}})
+	m = updated.(Model)
+	if !m.paused || len(m.filteredLines) != 50 {
+		t.Fatal("Expected the view to pause")
+	}
+
+	m = runCommandLine(m, "set follow")
+	if !m.following || m.paused {
+		t.Fatal("Expected :set follow to resume")
+	}
+	if len(m.filteredLines) != 51 || m.filteredLines[m.cursor] != "INFO a" {
+		t.Errorf("Expected to catch up with the held line, got %d lines", len(m.filteredLines))
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	for n, want := range map[int]string{0: "0", 999: "999", 1234: "1,234", 1234567: "1,234,567"} {
+		if got := formatCount(n); got != want {
+			t.Errorf("formatCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
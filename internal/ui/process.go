@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+// stderrStyle tints the lines a command wrote to stderr.
+var stderrStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6E6E"))
+
+// process is a command lv runs itself (lv -- cmd args), its stdout and stderr
+// read as two streams. A restart replaces it with a new process.
+type process struct {
+	args    []string
+	cfg     StreamerConfig
+	cmd     *exec.Cmd
+	out     *Streamer
+	errs    *Streamer
+	started time.Time
+	exited  time.Time // Zero while running
+	err     error     // From Wait: nil for exit status 0
+}
+
+// processExitMsg reports that a command exited.
+type processExitMsg struct {
+	proc *process
+	at   time.Time
+	err  error
+}
+
+// startProcess runs args with stdout and stderr on pipes. The command gets
+// its own process group so a restart stops its children too, and no stdin so
+// it leaves the terminal to lv.
+func startProcess(args []string, cfg StreamerConfig) (*process, error) {
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		outR.Close()
+		outW.Close()
+		return nil, err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout, cmd.Stderr = outW, errW
+	setProcessGroup(cmd)
+	err = cmd.Start()
+	// The child holds its own copies; the pipes end when it closes them.
+	outW.Close()
+	errW.Close()
+	if err != nil {
+		outR.Close()
+		errR.Close()
+		return nil, err
+	}
+
+	return &process{
+		args:    args,
+		cfg:     cfg,
+		cmd:     cmd,
+		out:     NewStreamerWithConfig(outR, cfg),
+		errs:    NewStreamerWithConfig(errR, cfg),
+		started: statsNow(),
+	}, nil
+}
+
+// wait delivers the exit of the command.
+func (p *process) wait() tea.Cmd {
+	return func() tea.Msg {
+		err := p.cmd.Wait()
+		return processExitMsg{proc: p, at: statsNow(), err: err}
+	}
+}
+
+// owns reports whether st reads the output of p.
+func (p *process) owns(st *Streamer) bool {
+	return p != nil && st != nil && (st == p.out || st == p.errs)
+}
+
+// stop kills the command and its children, and drops output not read yet.
+func (p *process) stop() {
+	if p.exited.IsZero() {
+		killProcessGroup(p.cmd)
+	}
+	discardStream(p.out)
+	discardStream(p.errs)
+}
+
+// discardStream reads st to its end so its goroutine can finish.
+func discardStream(st *Streamer) {
+	go func() {
+		lines, errs := st.lines, st.err
+		for lines != nil || errs != nil {
+			select {
+			case _, ok := <-lines:
+				if !ok {
+					lines = nil
+				}
+			case _, ok := <-errs:
+				if !ok {
+					errs = nil
+				}
+			}
+		}
+	}()
+}
+
+// status describes how the command ended.
+func (p *process) status() string {
+	var exitErr *exec.ExitError
+	switch {
+	case p.err == nil:
+		return "exit 0"
+	case errors.As(p.err, &exitErr) && exitErr.ExitCode() >= 0:
+		return fmt.Sprintf("exit %d", exitErr.ExitCode())
+	}
+	return p.err.Error()
+}
+
+// newCommandStore starts args and returns the store its output goes to.
+func newCommandStore(args []string, cfg config.Config) (*lineStore, error) {
+	p, err := startProcess(args, streamerConfig(cfg, "Stdin"))
+	if err != nil {
+		return nil, err
+	}
+	return &lineStore{name: strings.Join(args, " "), proc: p}, nil
+}
+
+// NewCommandModel runs args and shows its output as it arrives, following
+// it unless follow is off.
+func NewCommandModel(args []string, cfg config.Config) (Model, error) {
+	if len(args) == 0 {
+		return Model{}, errors.New("no command given")
+	}
+	applyConfig(cfg)
+	store, err := newCommandStore(args, cfg)
+	if err != nil {
+		return Model{}, err
+	}
+	m := newModelWithStore(store, cfg)
+	m.following = cfg.Follow != "off"
+	if msg := configStatus(cfg); msg != "" {
+		m.statusMsg = msg
+	}
+	return m, nil
+}
+
+// Close stops the commands lv started and the file watchers.
+func (m Model) Close() {
+	for _, s := range m.stores() {
+		s.close()
+	}
+}
+
+// handleProcessMsg takes the output and exit of commands.
+func (m *Model) handleProcessMsg(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case LogChunkMsg:
+		s := m.findStore(func(s *lineStore) bool { return s.proc.owns(msg.stream) })
+		if s == nil {
+			return nil, false
+		}
+		var cmds []tea.Cmd
+		switch {
+		case errors.Is(msg.Err, io.EOF):
+			return nil, true
+		case msg.Err != nil:
+			m.statusMsg = fmt.Sprintf("Error reading %s: %v", s.name, msg.Err)
+		case len(msg.Lines) > 0:
+			if msg.stream == s.proc.errs {
+				for i := range msg.Lines {
+					s.stderr = append(s.stderr, len(s.lines)+i)
+				}
+			}
+			cmds = append(cmds, m.receiveLines(s, msg.Lines))
+		}
+		if m.holding(s) {
+			s.heldStreams = append(s.heldStreams, msg.stream)
+			m.hold(s)
+		} else {
+			cmds = append(cmds, WaitForStream(msg.stream))
+		}
+		return tea.Batch(cmds...), true
+
+	case processExitMsg:
+		p := msg.proc
+		p.exited, p.err = msg.at, msg.err
+		for _, pane := range m.panes() {
+			if pane.store.proc == p {
+				m.statusMsg = fmt.Sprintf("%s: %s after %s", pane.store.name, p.status(), formatRuntime(p.exited.Sub(p.started)))
+			}
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// restartCommand stops the command of the view and runs it again. Its output
+// follows that of the last run, after a separator line.
+func (m *Model) restartCommand() tea.Cmd {
+	s := m.store
+	if s.proc == nil {
+		m.statusMsg = "Only a command can be restarted (lv -- <command>)"
+		return nil
+	}
+	s.proc.stop()
+	s.heldStreams = nil
+	p, err := startProcess(s.proc.args, s.proc.cfg)
+	if err != nil {
+		m.statusMsg = "Cannot restart: " + err.Error()
+		return nil
+	}
+	s.proc = p
+	cmd := m.receiveLines(s, []string{fmt.Sprintf("── restarted %s at %s ──", s.name, p.started.Format("15:04:05"))})
+	m.statusMsg = "Restarted " + s.name
+	return tea.Batch(cmd, s.wait())
+}
+
+// isStderr reports whether store line idx came from the command's stderr.
+func (s *lineStore) isStderr(idx int) bool {
+	i := sort.SearchInts(s.stderr, idx)
+	return i < len(s.stderr) && s.stderr[i] == idx
+}
+
+// streamMarkWidth is the width of the stdout / stderr mark, shown for
+// commands only.
+func (m Model) streamMarkWidth() int {
+	if m.store.proc == nil {
+		return 0
+	}
+	return 1
+}
+
+// streamMark marks a row of stderr output with a tinted bar.
+func (m Model) streamMark(row int) string {
+	if m.store.proc == nil {
+		return ""
+	}
+	if row >= 0 && row < len(m.filteredRefs) && m.store.isStderr(m.filteredRefs[row].first) {
+		return stderrStyle.Render("▌")
+	}
+	return " "
+}
+
+// tintStderr colors a row of stderr output that no other rule colored.
+func (m Model) tintStderr(row int, line string) string {
+	if len(m.store.stderr) == 0 || row < 0 || row >= len(m.filteredRefs) || !m.store.isStderr(m.filteredRefs[row].first) {
+		return line
+	}
+	if line != stripAnsi(line) {
+		return line
+	}
+	return stderrStyle.Render(line)
+}
+
+// processView shows whether the command runs, or how it ended, and for how
+// long.
+func (m Model) processView() string {
+	p := m.store.proc
+	if p == nil {
+		return ""
+	}
+	if p.exited.IsZero() {
+		return footerStyle.Render("│ ") + infoStyleLog.Render("●") + footerStyle.Render(" running "+formatRuntime(statsNow().Sub(p.started))+" ")
+	}
+	style := infoStyleLog
+	if p.err != nil {
+		style = errorStyle
+	}
+	return footerStyle.Render("│ ") + style.Render(p.status()) + footerStyle.Render(" after "+formatRuntime(p.exited.Sub(p.started))+" ")
+}
+
+// formatRuntime rounds d to tenths of a second, or to seconds past a minute.
+func formatRuntime(d time.Duration) string {
+	if d >= time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
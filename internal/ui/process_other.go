@@ -0,0 +1,13 @@
+//go:build !unix
+
+package ui
+
+import "os/exec"
+
+// setProcessGroup does nothing where process groups are not available.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills cmd. Its children are left running.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
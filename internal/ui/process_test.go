@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestCommandOutputAndRestart(t *testing.T) {
+	m, err := NewCommandModel([]string{"sh", "-c", "echo INFO out; sleep 0.1; echo oops >&2; exit 3"}, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	m = resize(m, 120, 24)
+
+	// Deliver both streams to their end, then the exit.
+	run := func(m Model) Model {
+		p := m.store.proc
+		for _, st := range []*Streamer{p.out, p.errs} {
+			for {
+				msg := WaitForStream(st)()
+				updated, _ := m.Update(msg)
+				m = updated.(Model)
+				if msg.(LogChunkMsg).Err != nil {
+					break
+				}
+			}
+		}
+		updated, _ := m.Update(p.wait()())
+		return updated.(Model)
+	}
+	m = run(m)
+
+	if len(m.filteredLines) != 2 || m.store.isStderr(0) || !m.store.isStderr(1) {
+		t.Fatalf("Expected stdout then stderr lines, got %q (stderr %v)", m.filteredLines, m.store.stderr)
+	}
+	if mark := stripAnsi(m.streamMark(1)); mark != "▌" {
+		t.Errorf("Expected the stderr line to be marked, got %q", mark)
+	}
+	footer := stripAnsi(m.footerView())
+	if !strings.Contains(footer, "exit 3 after") || !strings.Contains(m.statusMsg, "exit 3") {
+		t.Errorf("Expected the exit status in the footer, got %q", footer)
+	}
+
+	m = pressKeys(m, "r")
+	if !m.store.proc.exited.IsZero() || !strings.Contains(stripAnsi(m.footerView()), "running") {
+		t.Fatal("Expected r to run the command again")
+	}
+	m = run(m)
+	if len(m.filteredLines) != 5 || !strings.HasPrefix(m.filteredLines[2], "── restarted sh -c") || !m.store.isStderr(4) {
+		t.Errorf("Expected the second run after a separator, got %q", m.filteredLines)
+	}
+}
+
+func TestCommandBurstArrivesWhileRunning(t *testing.T) {
+	m, err := NewCommandModel([]string{"sh", "-c", "echo INFO a; echo INFO b; sleep 10"}, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	m = receiveLines(t, m, m.store.proc.out, 2)
+	if strings.Join(m.filteredLines, "\n") != "INFO a\nINFO b" {
+		t.Errorf("Expected both lines before the command exits, got %q", m.filteredLines)
+	}
+}
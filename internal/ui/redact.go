@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+// redactor masks one kind of secret. When re has a capture group only the
+// group is masked (e.g. the value of "password=..."). hint is a cheap
+// substring test that lets most lines skip the regex; valid, when set, vetoes
+// false positives.
+type redactor struct {
+	re    *regexp.Regexp
+	hint  func(line string) bool
+	valid func(match string) bool
+}
+
+var builtinRedactors = map[string]redactor{
+	"email": {
+		re:   regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		hint: func(l string) bool { return strings.IndexByte(l, '@') >= 0 },
+	},
+	"bearer": {
+		re:   regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+		hint: func(l string) bool { return strings.Contains(strings.ToLower(l), "bearer") },
+	},
+	"jwt": {
+		re:   regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+		hint: func(l string) bool { return strings.Contains(l, "eyJ") },
+	},
+	"card": {
+		re:    regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		hint:  hasDigits(13),
+		valid: luhnValid,
+	},
+	"aws": {
+		re:   regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		hint: func(l string) bool { return strings.Contains(l, "AKIA") || strings.Contains(l, "ASIA") },
+	},
+	"secret": {
+		re:   regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret)["']?\s*[:=]\s*["']?([^\s"',;&}]+)`),
+		hint: func(l string) bool { return strings.ContainsAny(l, ":=") },
+	},
+}
+
+var (
+	// redactors are the detectors enabled by config, in config order.
+	redactors []redactor
+	// redactMask replaces every detected secret.
+	redactMask = "[REDACTED]"
+)
+
+// setRedaction compiles the configured detectors and custom patterns.
+func setRedaction(cfg config.Redact) {
+	redactors = nil
+	for _, name := range cfg.Detectors {
+		if r, ok := builtinRedactors[name]; ok {
+			redactors = append(redactors, r)
+		}
+	}
+	for _, p := range cfg.Patterns {
+		if re, err := regexp.Compile(p); err == nil {
+			redactors = append(redactors, redactor{re: re})
+		}
+	}
+	redactMask = cfg.Mask
+	if redactMask == "" {
+		redactMask = "[REDACTED]"
+	}
+}
+
+// redactLine masks every secret found by the enabled detectors.
+func redactLine(line string) string {
+	for _, r := range redactors {
+		if r.hint != nil && !r.hint(line) {
+			continue
+		}
+		line = r.mask(line)
+	}
+	return line
+}
+
+func (r redactor) mask(line string) string {
+	matches := r.re.FindAllStringSubmatchIndex(line, -1)
+	if matches == nil {
+		return line
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range matches {
+		start, end := loc[0], loc[1]
+		if len(loc) >= 4 && loc[2] >= 0 {
+			start, end = loc[2], loc[3]
+		}
+		if r.valid != nil && !r.valid(line[start:end]) {
+			continue
+		}
+		b.WriteString(line[last:start])
+		b.WriteString(redactMask)
+		last = end
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// hasDigits reports lines with at least n digits, the minimum for a card number.
+func hasDigits(n int) func(string) bool {
+	return func(l string) bool {
+		count := 0
+		for i := 0; i < len(l); i++ {
+			if l[i] >= '0' && l[i] <= '9' {
+				count++
+				if count >= n {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// luhnValid checks the card number checksum, ignoring spaces and dashes.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// toggleRedaction switches between masked and revealed secrets.
+func (m *Model) toggleRedaction() {
+	m.redact = !m.redact
+	m.layoutCache = make(map[int][]string)
+	m.applyFilters(false)
+	if m.redact {
+		m.statusMsg = "Secrets redacted"
+	} else {
+		m.statusMsg = "Secrets revealed"
+	}
+}
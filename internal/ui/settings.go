@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+var (
+	// timeLocation is used for timestamps that carry no zone.
+	timeLocation = time.UTC
+	// recordFormat restricts structured parsing: auto, json, logfmt or text.
+	recordFormat = "auto"
+)
+
+// applyConfig sets the package-wide parsing settings from cfg.
+func applyConfig(cfg config.Config) {
+	if loc, err := cfg.Location(); err == nil {
+		timeLocation = loc
+	}
+	if cfg.Format != "" {
+		recordFormat = cfg.Format
+	}
+	setLevelKeywords(cfg.Levels)
+	applyTheme(cfg)
+	setHighlightRules(cfg.Highlights)
+	setRedaction(cfg.Redact)
+	setTraceRules(cfg.Trace)
+	setAlertRules(cfg.Alerts)
+}
+
+// streamerConfig picks batch sizes for the source: file startup backfill
+// favors throughput, stdin favors latency.
+func streamerConfig(cfg config.Config, filename string) StreamerConfig {
+	if filename != "Stdin" {
+		return StreamerConfig{
+			BatchLines: cfg.Stream.FileBatchLines,
+			FlushEvery: cfg.Stream.FileFlushEvery.Duration,
+		}
+	}
+	return StreamerConfig{
+		BatchLines: cfg.Stream.StdinBatchLines,
+		FlushEvery: cfg.Stream.StdinFlushEvery.Duration,
+	}
+}
+
+// exportView writes the current filtered view to a timestamped file in the
+// export directory and reports the result in the footer.
+func (m *Model) exportView() {
+	if err := os.MkdirAll(m.exportDir, 0o755); err != nil {
+		m.statusMsg = "Export failed: " + err.Error()
+		return
+	}
+	name := fmt.Sprintf("lv-export-%s.log", time.Now().Format("20060102-150405"))
+	m.exportTo(filepath.Join(m.exportDir, name))
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestNewModelAppliesConfig(t *testing.T) {
+	lines := []string{
+		"2023-01-01 10:00:00 FATAL out of memory",
+		"2023-01-01 10:00:01 INFO ok",
+	}
+
+	cfg := config.Default()
+	cfg.Wrap = true
+	cfg.Fold = true
+	cfg.Levels.Error = []string{"ERROR", "FATAL"}
+	cfg.Export.Dir = t.TempDir()
+
+	m := NewModel("test.log", lines, nil, cfg)
+	t.Cleanup(func() { applyConfig(config.Default()) })
+
+	if !m.wrap || !m.foldStackTraces {
+		t.Error("Expected wrap and fold defaults from config")
+	}
+
+	m.showError = false
+	m.applyFilters(true)
+	if len(m.filteredLines) != 1 || m.filteredLines[0] != lines[1] {
+		t.Errorf("Expected FATAL to count as an error level, got %q", m.filteredLines)
+	}
+
+	m.exportView()
+	files, _ := filepath.Glob(filepath.Join(cfg.Export.Dir, "lv-export-*.log"))
+	if len(files) != 1 {
+		t.Fatalf("Expected one export file, got %v (status %q)", files, m.statusMsg)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.TrimSpace(string(data)) != lines[1] {
+		t.Errorf("Unexpected export content %q", data)
+	}
+}
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A split shows two views of the tab at once. The focused view is the Model
+// itself; the other pane's view is kept in split and drawn next to it. Each
+// pane has its own filters and scroll position, and its own label row.
+
+// panes returns the focused view and, when split, the other one.
+func (m *Model) panes() []*Model {
+	if len(m.split) == 0 {
+		return []*Model{m}
+	}
+	return []*Model{m, &m.split[0]}
+}
+
+// openSplit splits the tab. With no path the new pane shows a copy of the
+// current view; otherwise it shows the file, sharing its lines when open.
+func (m *Model) openSplit(path string, vertical bool) (tea.Cmd, error) {
+	var pane Model
+	var cmd tea.Cmd
+	if path == "" {
+		pane = *m
+		pane.tabs = nil
+		pane.undoStack, pane.redoStack = nil, nil
+		pane.bookmarks = copyBookmarks(m.bookmarks)
+		pane.bookmarkNotes = copyNotes(m.bookmarkNotes)
+		pane.detailFolds = make(map[string]bool)
+	} else {
+		store, err := m.openStore(path)
+		if err != nil {
+			return nil, err
+		}
+		if !containsStore(m.stores(), store) {
+			cmd = store.wait()
+		}
+		pane = newModelWithStore(store, m.cfg)
+		pane.history = m.history
+	}
+	pane.split = nil
+	pane.layoutCache = make(map[int][]string)
+
+	m.split = []Model{pane}
+	m.splitVertical = vertical
+	m.splitFirst = true
+	m.layoutPanes()
+	return cmd, nil
+}
+
+// closeSplit keeps only the focused pane.
+func (m *Model) closeSplit() {
+	if len(m.split) == 0 {
+		m.statusMsg = "Not split"
+		return
+	}
+	closing := m.split[0].store
+	m.split = nil
+	m.timeSync = false
+	m.layoutPanes()
+	if !containsStore(m.stores(), closing) {
+		closing.close()
+	}
+}
+
+// focusOtherPane moves the focus, and with it the keyboard, to the other
+// pane. What belongs to the window and the tab moves along.
+func (m *Model) focusOtherPane() {
+	if len(m.split) == 0 {
+		return
+	}
+	other := m.split[0]
+	current := *m
+	current.split, current.tabs = nil, nil
+
+	other.split = []Model{current}
+	other.tabs, other.activeTab = m.tabs, m.activeTab
+	other.splitVertical, other.splitFirst, other.timeSync = m.splitVertical, !m.splitFirst, m.timeSync
+	other.takeWindowState(*m)
+	other.skipUndoRecord = true
+	*m = other
+	m.layoutPanes()
+}
+
+// takeWindowState copies what belongs to the window rather than to a view.
+func (m *Model) takeWindowState(from Model) {
+	m.ready, m.winWidth, m.winHeight = from.ready, from.winWidth, from.winHeight
+	m.viewport = from.viewport
+	m.timelineViewport.Width, m.timelineViewport.Height = from.timelineViewport.Width, from.timelineViewport.Height
+	m.cfg, m.keys, m.history = from.cfg, from.keys, from.history
+	m.alerts = from.alerts
+	m.osc52, m.osc52Gen = from.osc52, from.osc52Gen
+	m.layoutCache = make(map[int][]string)
+}
+
+// layoutPanes sizes the panes to the window: stacked for :split, side by
+// side for :vsplit. Each pane gives one row to its label.
+func (m *Model) layoutPanes() {
+	if m.winWidth == 0 && m.winHeight == 0 {
+		return
+	}
+	bodyHeight := max(0, m.winHeight-m.headerHeight-m.footerHeight)
+	if len(m.split) == 0 {
+		m.screenWidth, m.viewport.Height = m.winWidth, bodyHeight
+		return
+	}
+
+	first, second := m, &m.split[0]
+	if !m.splitFirst {
+		first, second = second, first
+	}
+	if m.splitVertical {
+		w := (m.winWidth - 1) / 2 // One column for the divider
+		first.screenWidth, second.screenWidth = w, m.winWidth-1-w
+		first.viewport.Height, second.viewport.Height = max(0, bodyHeight-1), max(0, bodyHeight-1)
+	} else {
+		h := bodyHeight / 2
+		first.screenWidth, second.screenWidth = m.winWidth, m.winWidth
+		first.viewport.Height, second.viewport.Height = max(0, h-1), max(0, bodyHeight-h-1)
+	}
+	for _, p := range []*Model{first, second} {
+		p.viewport.Width = 20000
+		p.layoutCache = make(map[int][]string)
+		p.setCursor(p.cursor)
+	}
+}
+
+// paneOrigin is where the focused pane's rows start, relative to the top
+// left of the body, for translating mouse positions.
+func (m Model) paneOrigin() (x, y int) {
+	if len(m.split) == 0 || m.splitFirst {
+		return 0, 1
+	}
+	other := m.split[0]
+	if m.splitVertical {
+		return other.screenWidth + 1, 1
+	}
+	return 0, other.viewport.Height + 2
+}
+
+// routeMouse translates a mouse event to the focused pane's coordinates,
+// moving the focus first when a click lands in the other pane. It returns
+// false for events outside the panes' rows.
+func (m *Model) routeMouse(msg tea.MouseMsg) (tea.MouseMsg, bool) {
+	if len(m.split) == 0 {
+		return msg, true
+	}
+	inPane := func() bool {
+		x, y := m.paneOrigin()
+		top := m.headerHeight + y
+		return msg.X >= x && msg.X < x+m.screenWidth && msg.Y >= top && msg.Y < top+m.viewport.Height
+	}
+	if !inPane() {
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return msg, false
+		}
+		m.focusOtherPane()
+		if !inPane() {
+			return msg, false
+		}
+	}
+	x, y := m.paneOrigin()
+	msg.X -= x
+	msg.Y -= y
+	return msg, true
+}
+
+// syncPaneTime scrolls the other pane to the time of the focused pane's top
+// line when time sync is on.
+func (m *Model) syncPaneTime() {
+	if !m.timeSync || len(m.split) == 0 || m.yOffset >= len(m.filteredRefs) {
+		return
+	}
+	t, ok := m.timeIndex().at(m.filteredRefs[m.yOffset].first)
+	if !ok {
+		return
+	}
+	o := &m.split[0]
+	row := min(o.rowAtTime(t), len(o.filteredLines)-1)
+	if row < 0 {
+		return
+	}
+	o.yOffset = max(0, min(row, len(o.filteredLines)-o.pageHeight()))
+	o.setCursor(row)
+}
+
+// paneLabel is the row above a pane naming its file and filter.
+func (m Model) paneLabel(focused bool, width int) string {
+	label := " " + filepath.Base(m.filename)
+	if m.filterText != "" {
+		label += " /" + m.filterText
+	}
+	label += " "
+	if m.timeSync && focused {
+		label += "⏱ "
+	}
+	style := mutedStyle
+	if focused {
+		style = helpKeyStyle.Bold(true)
+	}
+	label = style.Render(label)
+	return label + mutedStyle.Render(strings.Repeat("─", max(0, width-lipgloss.Width(label))))
+}
+
+// splitView draws both panes, the focused one from its rendered body.
+func (m Model) splitView(body string) string {
+	other := m.split[0]
+	focused := m.paneLabel(true, m.screenWidth) + "\n" + fitBlock(body, m.screenWidth, m.viewport.Height)
+	unfocused := other.paneLabel(false, other.screenWidth) + "\n" + fitBlock(other.bodyView(), other.screenWidth, other.viewport.Height)
+
+	first, second := focused, unfocused
+	if !m.splitFirst {
+		first, second = second, first
+	}
+	if !m.splitVertical {
+		return first + "\n" + second
+	}
+	height := m.viewport.Height + 1
+	divider := mutedStyle.Render(strings.TrimSuffix(strings.Repeat("│\n", height), "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, first, divider, second)
+}
+
+// fitBlock cuts or pads every line of s to width cells and s to height lines.
+func fitBlock(s string, width, height int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	cut := lipgloss.NewStyle().MaxWidth(width)
+	for i, l := range lines {
+		l = cut.Render(l)
+		lines[i] = l + strings.Repeat(" ", max(0, width-lipgloss.Width(l)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func resize(m Model, w, h int) Model {
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: w, Height: h})
+	return updated.(Model)
+}
+
+func TestSplitPanesKeepTheirOwnFilters(t *testing.T) {
+	m := resize(InitialModel("test.log", numberedLines(40), nil), 80, 24)
+
+	m = runCommandLine(m, "vsplit")
+	m = runCommandLine(m, "filter ERROR")
+	if len(m.split) != 1 || m.split[0].filterText != "" {
+		t.Fatal("Expected the filter to apply to the focused pane only")
+	}
+	if m.screenWidth+m.split[0].screenWidth != 79 {
+		t.Errorf("Expected the panes to share the width, got %d and %d", m.screenWidth, m.split[0].screenWidth)
+	}
+
+	view := m.View()
+	rows := strings.Split(view, "\n")
+	for _, line := range rows[m.headerHeight : len(rows)-m.footerHeight] {
+		if w := lipgloss.Width(line); w != 80 {
+			t.Fatalf("Expected pane rows to fit the window, got width %d: %q", w, stripAnsi(line))
+		}
+	}
+	if !strings.Contains(view, "test.log /ERROR") || !strings.Contains(view, "ERROR line 11") || !strings.Contains(view, "INFO line 2") {
+		t.Error("Expected both panes on screen")
+	}
+
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyCtrlW})
+	if m.filterText != "" || m.split[0].filterText != "ERROR" || m.splitFirst {
+		t.Errorf("Expected ctrl+w to focus the unfiltered right pane, got %q", m.filterText)
+	}
+
+	// A click in the other pane focuses it.
+	updated, _ := m.Update(tea.MouseMsg{X: 5, Y: 6, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	m = updated.(Model)
+	if m.filterText != "ERROR" {
+		t.Error("Expected a click in the left pane to focus it")
+	}
+
+	m = runCommandLine(m, "only")
+	if len(m.split) != 0 || m.screenWidth != 80 {
+		t.Error("Expected :only to close the other pane and take the full width")
+	}
+}
+
+func TestSplitTimeSync(t *testing.T) {
+	dir := t.TempDir()
+	server := filepath.Join(dir, "server.log")
+	var serverLines []string
+	for i := 0; i < 60; i++ {
+		serverLines = append(serverLines, fmt.Sprintf("2024-01-01 10:%02d:30 INFO server %d", i, i))
+	}
+	os.WriteFile(server, []byte(strings.Join(serverLines, "\n")+"\n"), 0o644)
+
+	var client []string
+	for i := 0; i < 120; i++ {
+		client = append(client, fmt.Sprintf("2024-01-01 10:%02d:%02d INFO client %d", i/2, (i%2)*30, i))
+	}
+	m := resize(InitialModel("client.log", client, nil), 80, 24)
+	m = runCommandLine(m, "split "+server)
+	m = runCommandLine(m, "sync")
+	if !m.timeSync {
+		t.Fatal("Expected :sync to turn time sync on")
+	}
+
+	m = runCommandLine(m, "41") // client 40 is 10:20:00
+	m = pressKeys(m, "d")
+	top := m.filteredLines[m.yOffset]
+	other := m.split[0]
+	var minute int
+	fmt.Sscanf(top, "2024-01-01 10:%d", &minute)
+	if got := other.filteredLines[other.yOffset]; !strings.HasPrefix(got, fmt.Sprintf("2024-01-01 10:%02d:30", minute)) {
+		t.Errorf("Expected the server pane at the client's time (%q), got %q", top, got)
+	}
+}
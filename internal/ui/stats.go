@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// rateBuckets is how many seconds of volume the sparkline shows.
+	rateBuckets = 20
+	// rateAverage is how many seconds lines/sec is averaged over.
+	rateAverage = 5
+)
+
+// statsNow is the clock for ingestion rates, replaced in tests.
+var statsNow = time.Now
+
+// sparkBars draw the sparkline, from no lines to the busiest second.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// ingestRate counts the lines a source delivered in each of the last
+// rateBuckets seconds.
+type ingestRate struct {
+	counts [rateBuckets]int
+	last   int64 // Unix second of counts[rateBuckets-1]
+}
+
+// add records n lines arriving at now.
+func (r *ingestRate) add(now time.Time, n int) {
+	r.advance(now.Unix())
+	r.counts[rateBuckets-1] += n
+}
+
+// advance moves the window so that its newest bucket is second sec.
+func (r *ingestRate) advance(sec int64) {
+	shift := sec - r.last
+	if shift <= 0 {
+		return
+	}
+	if shift >= rateBuckets {
+		r.counts = [rateBuckets]int{}
+	} else {
+		copy(r.counts[:], r.counts[shift:])
+		for i := rateBuckets - int(shift); i < rateBuckets; i++ {
+			r.counts[i] = 0
+		}
+	}
+	r.last = sec
+}
+
+// series returns the per-second counts up to now, oldest first.
+func (r ingestRate) series(now time.Time) [rateBuckets]int {
+	r.advance(now.Unix())
+	return r.counts
+}
+
+// perSecond is the average rate over the last rateAverage seconds.
+func (r ingestRate) perSecond(now time.Time) float64 {
+	counts := r.series(now)
+	sum := 0
+	for _, n := range counts[rateBuckets-rateAverage:] {
+		sum += n
+	}
+	return float64(sum) / rateAverage
+}
+
+// sparkline draws counts as bars scaled to the largest one.
+func sparkline(counts []int) string {
+	peak := 0
+	for _, n := range counts {
+		peak = max(peak, n)
+	}
+	var b strings.Builder
+	for _, n := range counts {
+		i := 0
+		if peak > 0 && n > 0 {
+			i = 1 + n*(len(sparkBars)-2)/peak
+		}
+		b.WriteRune(sparkBars[min(i, len(sparkBars)-1)])
+	}
+	return b.String()
+}
+
+// statsTickMsg refreshes the rate in the footer once a second.
+type statsTickMsg struct{}
+
+func statsTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return statsTickMsg{} })
+}
+
+// rateView is the lines/sec and sparkline of the tab's source, empty when it
+// delivered nothing recently.
+func (m Model) rateView() string {
+	now := statsNow()
+	counts := m.store.rate.series(now)
+	active := false
+	for _, n := range counts {
+		active = active || n > 0
+	}
+	if !active {
+		return ""
+	}
+	return fmt.Sprintf("│ %s/s %s ", formatRate(m.store.rate.perSecond(now)), sparkline(counts[:]))
+}
+
+func formatRate(r float64) string {
+	switch {
+	case r >= 10000:
+		return fmt.Sprintf("%.0fk", r/1000)
+	case r >= 1000:
+		return fmt.Sprintf("%.1fk", r/1000)
+	case r >= 10:
+		return fmt.Sprintf("%.0f", r)
+	}
+	return fmt.Sprintf("%.1f", r)
+}
+
+// countLevels extends the per-level counts of the view over rows added since
+// the last call; applyFilters starts them over.
+func (m *Model) countLevels() {
+	for _, line := range m.filteredLines[m.levelsCounted:] {
+		for lvl := levelError; lvl <= levelDebug; lvl++ {
+			if hasLevel(line, lvl) {
+				m.levelCounts[lvl]++
+				break
+			}
+		}
+	}
+	m.levelsCounted = len(m.filteredLines)
+}
+
+// levelCountsView shows how many rows of the view have each level.
+func (m Model) levelCountsView() string {
+	var parts []string
+	for lvl, name := range []string{"E", "W", "I", "D"} {
+		parts = append(parts, levelStyle(logLevel(lvl)).Render(name)+footerStyle.Render(fmt.Sprintf(":%d", m.levelCounts[lvl])))
+	}
+	return footerStyle.Render("│ ") + strings.Join(parts, " ") + " "
+}
+
+// sourceView reports a source that stopped: a stream at its end, or an error
+// reading or watching it.
+func (m Model) sourceView() string {
+	switch {
+	case m.store.err != nil:
+		return footerStyle.Render("│ ") + errorStyle.Render("SOURCE ERROR") + " "
+	case m.store.ended:
+		return footerStyle.Render("│ ") + mutedStyle.Render("ENDED") + " "
+	}
+	return ""
+}
+
+// sourceFailed records an error from the source of s. A stream stops there;
+// a watched file may recover, which clears the error.
+func (m *Model) sourceFailed(s *lineStore, err error) {
+	if errors.Is(err, io.EOF) {
+		// A streamed file that has been read to the end is still watched.
+		s.ended = s.watcher == nil
+		return
+	}
+	s.err = err
+	for _, p := range m.panes() {
+		if p.store == s {
+			m.statusMsg = fmt.Sprintf("Error reading %s: %v", s.name, err)
+		}
+	}
+}
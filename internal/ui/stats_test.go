@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestIngestRate(t *testing.T) {
+	start := time.Unix(1000, 0)
+	var r ingestRate
+	r.add(start, 10)
+	r.add(start.Add(500*time.Millisecond), 10)
+	r.add(start.Add(2*time.Second), 30)
+
+	counts := r.series(start.Add(3 * time.Second))
+	if got := counts[rateBuckets-4:]; got[0] != 20 || got[1] != 0 || got[2] != 30 || got[3] != 0 {
+		t.Errorf("Unexpected buckets %v", got)
+	}
+	if got := r.perSecond(start.Add(3 * time.Second)); got != 10 {
+		t.Errorf("Expected 50 lines over 5s to be 10/s, got %v", got)
+	}
+	if counts := r.series(start.Add(time.Hour)); counts != [rateBuckets]int{} {
+		t.Error("Expected old buckets to be dropped")
+	}
+
+	if got := sparkline([]int{0, 1, 4, 8}); got != "▁▂▅█" {
+		t.Errorf("Unexpected sparkline %q", got)
+	}
+}
+
+func TestFooterShowsRateAndLevelCounts(t *testing.T) {
+	now := time.Unix(1000, 0)
+	statsNow = func() time.Time { return now }
+	defer func() { statsNow = time.Now }()
+
+	m := resize(InitialModel("test.log", numberedLines(20), nil), 200, 24)
+	if footer := stripAnsi(m.footerView()); !strings.Contains(footer, "E:2 W:0 I:18 D:0") || strings.Contains(footer, "/s") {
+		t.Fatalf("Expected level counts and no rate before lines arrive, got %q", footer)
+	}
+
+	updated, _ := m.Update(LogChunkMsg{Lines: []string{"WARN slow", "ERROR failed", "DEBUG x"}})
+	m = updated.(Model)
+	footer := stripAnsi(m.footerView())
+	if !strings.Contains(footer, "E:3 W:1 I:18 D:1") || !strings.Contains(footer, "0.6/s") {
+		t.Errorf("Expected updated counts and the rate, got %q", footer)
+	}
+
+	m = runCommandLine(m, "filter ERROR")
+	if footer := stripAnsi(m.footerView()); !strings.Contains(footer, "E:3 W:0 I:0 D:0") {
+		t.Errorf("Expected counts of the filtered view, got %q", footer)
+	}
+}
+
+func TestSourceEndAndErrors(t *testing.T) {
+	drain := func(m Model) Model {
+		for !m.store.ended {
+			updated, _ := m.Update(WaitForStream(m.store.streamer)())
+			m = updated.(Model)
+		}
+		return m
+	}
+
+	m := drain(resize(InitialModel("Stdin", nil, strings.NewReader("INFO a\nINFO b\n")), 200, 24))
+	if len(m.originalLines) != 2 || !strings.Contains(stripAnsi(m.footerView()), "ENDED") {
+		t.Errorf("Expected the stream to end after its lines, got %q", stripAnsi(m.footerView()))
+	}
+
+	broken := io.MultiReader(strings.NewReader("INFO a\n"), iotest.ErrReader(errors.New("connection reset")))
+	m = drain(resize(InitialModel("Stdin", nil, broken), 200, 24))
+	if footer := stripAnsi(m.footerView()); !strings.Contains(footer, "SOURCE ERROR") || !strings.Contains(footer, "connection reset") {
+		t.Errorf("Expected the read error in the footer, got %q", footer)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte("INFO a\n"), 0o644)
+	m = resize(InitialModel(path, []string{"INFO a"}, nil), 200, 24)
+	updated, _ := m.Update(FileChangeMsg{Filename: path, Error: errors.New("permission denied")})
+	m = updated.(Model)
+	if !strings.Contains(stripAnsi(m.footerView()), "SOURCE ERROR") {
+		t.Fatal("Expected the watch error in the footer")
+	}
+	updated, _ = m.Update(FileChangeMsg{Filename: path, NewContent: "INFO b\n", NewOffset: 14})
+	m = updated.(Model)
+	if m.store.err != nil || len(m.originalLines) != 2 {
+		t.Error("Expected new content to clear the error")
+	}
+}
@@ -18,6 +18,8 @@ const (
 type LogChunkMsg struct {
 	Lines []string
 	Err   error
+
+	stream *Streamer // Routes the chunk to its tab's line store
 }
 
 // Streamer manages the reading goroutine
@@ -51,21 +53,53 @@ func NewStreamerWithConfig(r io.Reader, cfg StreamerConfig) *Streamer {
 		err:   make(chan error),
 	}
 
+	// The scanner blocks on reads, so lines are handed to a second goroutine
+	// that can also flush on a timer: the last lines of a burst are sent
+	// within FlushEvery even when no more lines follow.
+	scanned := make(chan string)
+	var scanErr error
 	go func() {
 		scanner := bufio.NewScanner(r)
 		buf := make([]byte, 0, 64*1024)
 		scanner.Buffer(buf, 16*1024*1024) // allow very large log lines
+		for scanner.Scan() {
+			scanned <- scanner.Text()
+		}
+		scanErr = scanner.Err()
+		close(scanned)
+	}()
+
+	go func() {
 		var batch []string
+		var flush <-chan time.Time // Armed while a batch waits
 		lastSend := time.Now()
+		send := func() {
+			s.lines <- batch
+			batch = nil // Reset
+			flush = nil
+			lastSend = time.Now()
+		}
 
-		for scanner.Scan() {
-			batch = append(batch, scanner.Text())
-
-			// Flush if batch is big enough or time passed
-			if len(batch) >= cfg.BatchLines || time.Since(lastSend) > cfg.FlushEvery {
-				s.lines <- batch
-				batch = nil // Reset
-				lastSend = time.Now()
+		for scanned != nil {
+			select {
+			case line, ok := <-scanned:
+				if !ok {
+					scanned = nil
+					break
+				}
+				batch = append(batch, line)
+
+				// Flush if batch is big enough or time passed, otherwise
+				// once the interval since the last send is up
+				wait := cfg.FlushEvery - time.Since(lastSend)
+				switch {
+				case len(batch) >= cfg.BatchLines || wait < 0:
+					send()
+				case flush == nil:
+					flush = time.After(wait)
+				}
+			case <-flush:
+				send()
 			}
 		}
 
@@ -74,8 +108,8 @@ func NewStreamerWithConfig(r io.Reader, cfg StreamerConfig) *Streamer {
 			s.lines <- batch
 		}
 
-		if err := scanner.Err(); err != nil {
-			s.err <- err
+		if scanErr != nil {
+			s.err <- scanErr
 		}
 
 		close(s.lines)
@@ -85,20 +119,21 @@ func NewStreamerWithConfig(r io.Reader, cfg StreamerConfig) *Streamer {
 	return s
 }
 
-// WaitForStream waits for the next batch from the channel
+// WaitForStream waits for the next batch from the channel. The end of the
+// stream is reported as io.EOF.
 func WaitForStream(s *Streamer) tea.Cmd {
 	return func() tea.Msg {
 		select {
 		case lines, ok := <-s.lines:
 			if !ok {
-				return nil // EOF
+				return LogChunkMsg{Err: io.EOF, stream: s}
 			}
-			return LogChunkMsg{Lines: lines}
+			return LogChunkMsg{Lines: lines, stream: s}
 		case err, ok := <-s.err:
 			if !ok {
-				return nil
+				return LogChunkMsg{Err: io.EOF, stream: s}
 			}
-			return LogChunkMsg{Err: err}
+			return LogChunkMsg{Err: err, stream: s}
 		}
 	}
 }
@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	maxTableColWidth     = 40
+	tableColumnSample    = 200
+	tableColumnSeparator = " │ "
+)
+
+// preferredColumns lists well-known field names in display order. The first
+// alias present in the data is picked for each slot.
+var preferredColumns = [][]string{
+	{"time", "ts", "timestamp", "@timestamp"},
+	{"level", "lvl", "severity"},
+	{"service", "logger", "component"},
+	{"msg", "message"},
+	{"trace_id", "traceId", "request_id"},
+}
+
+var (
+	tableHeaderStyle         = lipgloss.NewStyle().Bold(true).Underline(true)
+	tableSelectedHeaderStyle = tableHeaderStyle.Reverse(true)
+)
+
+// sampleRecords parses up to tableColumnSample structured lines.
+func sampleRecords(lines []string) []Record {
+	var records []Record
+	for _, line := range lines {
+		if rec, ok := parseRecord(line); ok {
+			records = append(records, rec)
+			if len(records) >= tableColumnSample {
+				break
+			}
+		}
+	}
+	return records
+}
+
+// availableFields returns every field name seen in the sample, in first-seen order.
+func availableFields(lines []string) []string {
+	seen := make(map[string]struct{})
+	var fields []string
+	for _, rec := range sampleRecords(lines) {
+		for _, f := range rec {
+			if _, ok := seen[f.Key]; !ok {
+				seen[f.Key] = struct{}{}
+				fields = append(fields, f.Key)
+			}
+		}
+	}
+	return fields
+}
+
+// detectColumns picks default columns: the preferred fields that exist in the
+// data, or the first few fields if none of them do.
+func detectColumns(lines []string) []string {
+	fields := availableFields(lines)
+	present := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		present[f] = struct{}{}
+	}
+
+	var cols []string
+	for _, aliases := range preferredColumns {
+		for _, alias := range aliases {
+			if _, ok := present[alias]; ok {
+				cols = append(cols, alias)
+				break
+			}
+		}
+	}
+
+	if len(cols) == 0 {
+		cols = fields
+		if len(cols) > 5 {
+			cols = cols[:5]
+		}
+	}
+	return cols
+}
+
+// tableColumnWidths sizes each column to its widest value among lines,
+// capping all but the last column at maxTableColWidth.
+func tableColumnWidths(cols []string, lines []string) []int {
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		widths[i] = runewidth.StringWidth(c)
+	}
+	for _, line := range lines {
+		rec, ok := parseRecord(line)
+		if !ok {
+			continue
+		}
+		for i, c := range cols {
+			v, _ := rec.Get(c)
+			if w := runewidth.StringWidth(v); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	for i := 0; i < len(widths)-1; i++ {
+		if widths[i] > maxTableColWidth {
+			widths[i] = maxTableColWidth
+		}
+	}
+	return widths
+}
+
+// renderTableRow lays out a structured line as fixed width cells. ok is false
+// when the line cannot be parsed, in which case the caller shows it raw.
+func renderTableRow(line string, cols []string, widths []int) (string, bool) {
+	rec, ok := parseRecord(line)
+	if !ok {
+		return line, false
+	}
+	cells := make([]string, len(cols))
+	for i, c := range cols {
+		v, _ := rec.Get(c)
+		v = strings.ReplaceAll(v, "\n", " ")
+		if i == len(cols)-1 {
+			cells[i] = v
+		} else {
+			cells[i] = padCell(v, widths[i])
+		}
+	}
+	return strings.Join(cells, tableColumnSeparator), true
+}
+
+func padCell(v string, width int) string {
+	if runewidth.StringWidth(v) > width {
+		return runewidth.Truncate(v, width, "…")
+	}
+	return runewidth.FillRight(v, width)
+}
+
+func renderTableHeader(cols []string, widths []int, selected int) string {
+	cells := make([]string, len(cols))
+	for i, c := range cols {
+		label := c
+		if i < len(cols)-1 {
+			label = padCell(c, widths[i])
+		}
+		if i == selected {
+			cells[i] = tableSelectedHeaderStyle.Render(label)
+		} else {
+			cells[i] = tableHeaderStyle.Render(label)
+		}
+	}
+	return strings.Join(cells, tableColumnSeparator)
+}
+
+// sortByColumn stable-sorts lines by the value of col. Numbers come first in
+// either direction and compare numerically, then other values as strings;
+// rows without the field sort as empty values.
+func sortByColumn(lines []string, refs []lineRef, col string, desc bool) ([]string, []lineRef) {
+	keys := make([]string, len(lines))
+	nums := make([]float64, len(lines))
+	isNum := make([]bool, len(lines))
+	for i, line := range lines {
+		if rec, ok := parseRecord(line); ok {
+			keys[i], _ = rec.Get(col)
+		}
+		f, err := strconv.ParseFloat(keys[i], 64)
+		nums[i], isNum[i] = f, err == nil && !math.IsNaN(f)
+	}
+
+	idx := make([]int, len(lines))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		i, j := idx[a], idx[b]
+		if isNum[i] != isNum[j] {
+			return isNum[i]
+		}
+		if desc {
+			i, j = j, i
+		}
+		if isNum[i] {
+			return nums[i] < nums[j]
+		}
+		return keys[i] < keys[j]
+	})
+
+	sorted := make([]string, len(lines))
+	sortedRefs := make([]lineRef, len(refs))
+	for i, j := range idx {
+		sorted[i] = lines[j]
+		sortedRefs[i] = refs[j]
+	}
+	return sorted, sortedRefs
+}
+
+// parseColumnList parses a comma or space separated list of field names.
+func parseColumnList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// handleTableKey handles column selection, reordering, hiding and sorting
+// while the table view is active.
+func (m *Model) handleTableKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	n := len(m.tableColumns)
+	switch {
+	case key.Matches(msg, m.keys.TableNextColumn):
+		if n > 0 {
+			m.tableCursor = (m.tableCursor + 1) % n
+		}
+	case key.Matches(msg, m.keys.TablePrevColumn):
+		if n > 0 {
+			m.tableCursor = (m.tableCursor - 1 + n) % n
+		}
+	case key.Matches(msg, m.keys.TableMoveLeft):
+		if m.tableCursor > 0 && m.tableCursor < n {
+			c := m.tableCursor
+			m.tableColumns[c-1], m.tableColumns[c] = m.tableColumns[c], m.tableColumns[c-1]
+			m.tableCursor--
+		}
+	case key.Matches(msg, m.keys.TableMoveRight):
+		if m.tableCursor < n-1 {
+			c := m.tableCursor
+			m.tableColumns[c+1], m.tableColumns[c] = m.tableColumns[c], m.tableColumns[c+1]
+			m.tableCursor++
+		}
+	case key.Matches(msg, m.keys.TableHideColumn):
+		// Hide the selected column (keep at least one).
+		if n > 1 {
+			m.tableColumns = append(m.tableColumns[:m.tableCursor:m.tableCursor], m.tableColumns[m.tableCursor+1:]...)
+			if m.tableCursor >= len(m.tableColumns) {
+				m.tableCursor = len(m.tableColumns) - 1
+			}
+		}
+	case key.Matches(msg, m.keys.TableSort):
+		// Cycle sort on the selected column: ascending -> descending -> off.
+		if n == 0 {
+			return true, nil
+		}
+		col := m.tableColumns[m.tableCursor]
+		switch {
+		case m.tableSortCol != col:
+			m.tableSortCol, m.tableSortDesc = col, false
+		case !m.tableSortDesc:
+			m.tableSortDesc = true
+		default:
+			m.tableSortCol, m.tableSortDesc = "", false
+		}
+		m.applyFilters(true)
+	case key.Matches(msg, m.keys.TableColumns):
+		m.inputMode = ModeTableColumns
+		m.textInput.Placeholder = strings.Join(availableFields(m.filteredLines), ",")
+		m.textInput.SetValue(strings.Join(m.tableColumns, ","))
+		m.textInput.CursorEnd()
+		m.textInput.Focus()
+		m.resetHistoryBrowse()
+		return true, textinput.Blink
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m Model) tableHeaderView(widths []int) string {
+	cols := make([]string, len(m.tableColumns))
+	for i, c := range m.tableColumns {
+		cols[i] = c
+		if c == m.tableSortCol {
+			if m.tableSortDesc {
+				cols[i] += " ▼"
+			} else {
+				cols[i] += " ▲"
+			}
+			if i < len(widths) && widths[i] < runewidth.StringWidth(cols[i]) {
+				widths[i] = runewidth.StringWidth(cols[i])
+			}
+		}
+	}
+	return strings.Repeat(" ", m.gutterWidth()) + renderTableHeader(cols, widths, m.tableCursor)
+}
+
+// tableRowView renders one row of the table view, falling back to the raw line
+// for records that are not JSON or logfmt.
+func (m Model) tableRowView(i int, line string, widths []int) string {
+	row, _ := renderTableRow(line, m.tableColumns, widths)
+	plain := stripAnsi(row)
+	runes := []rune(plain)
+	if m.xOffset >= len(runes) {
+		return ""
+	}
+	end := min(len(runes), m.xOffset+m.screenWidth)
+	visible := string(runes[m.xOffset:end])
+	from := len(string(runes[:m.xOffset]))
+	visible = m.applyHighlightRulesSlice(highlightLine(highlightMatches(visible, m.regex)), plain, from)
+	if end == len(runes) {
+		visible += m.repeatBadge(i)
+	}
+	return m.gutter(i) + visible
+}
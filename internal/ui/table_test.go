@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRecord(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantOk   bool
+		wantKeys []string
+	}{
+		{`{"time":"2023-01-01T10:00:00Z","level":"info","msg":"hi","ctx":{"a":1}}`, true, []string{"time", "level", "msg", "ctx"}},
+		{`time=2023-01-01T10:00:00Z level=warn msg="disk almost full" pct=91`, true, []string{"time", "level", "msg", "pct"}},
+		{`2023-01-01 10:00:00 INFO plain text a=b`, false, nil},
+		{`{not json}`, false, nil},
+	}
+
+	for _, tt := range tests {
+		rec, ok := parseRecord(tt.line)
+		if ok != tt.wantOk {
+			t.Errorf("parseRecord(%q) ok = %v, want %v", tt.line, ok, tt.wantOk)
+			continue
+		}
+		var keys []string
+		for _, f := range rec {
+			keys = append(keys, f.Key)
+		}
+		if !reflect.DeepEqual(keys, tt.wantKeys) {
+			t.Errorf("parseRecord(%q) keys = %v, want %v", tt.line, keys, tt.wantKeys)
+		}
+	}
+
+	rec, _ := parseRecord(`level=warn msg="disk \"almost\" full"`)
+	if v, _ := rec.Get("msg"); v != `disk "almost" full` {
+		t.Errorf("Expected unescaped logfmt value, got %q", v)
+	}
+	rec, _ = parseRecord(`{"ctx":{"a": 1},"n":42}`)
+	if v, _ := rec.Get("ctx"); v != `{"a":1}` {
+		t.Errorf("Expected compact nested JSON, got %q", v)
+	}
+}
+
+func TestDetectColumns(t *testing.T) {
+	lines := []string{
+		`{"ts":"t1","msg":"a","level":"info","extra":1}`,
+		`{"ts":"t2","msg":"b","level":"warn","trace_id":"abc"}`,
+	}
+	got := detectColumns(lines)
+	want := []string{"ts", "level", "msg", "trace_id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("detectColumns = %v, want %v", got, want)
+	}
+}
+
+func TestSortByColumn(t *testing.T) {
+	lines := []string{
+		`{"msg":"b","dur":10}`,
+		`{"msg":"a","dur":9}`,
+		`unparseable`,
+		`{"msg":"c","dur":100}`,
+	}
+	refs := []lineRef{{first: 0, last: 0}, {first: 1, last: 1}, {first: 2, last: 2}, {first: 3, last: 3}}
+	got, gotRefs := sortByColumn(lines, refs, "dur", false)
+	want := []string{lines[1], lines[0], lines[3], lines[2]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortByColumn asc = %v, want %v", got, want)
+	}
+	if gotRefs[3] != refs[2] {
+		t.Errorf("Refs should follow their rows, got %v", gotRefs)
+	}
+
+	got, _ = sortByColumn(lines, refs, "dur", true)
+	if got[0] != lines[3] || got[3] != lines[2] {
+		t.Errorf("sortByColumn desc should start with the largest value and end with the missing one, got %v", got)
+	}
+
+	// Mixed values: numbers first, then strings, whatever the input order.
+	mixed := []string{`{"v":"b"}`, `{"v":10}`, `{"v":"NaN"}`, `{"v":"a"}`, `{"v":9}`, `{"v":"10x"}`}
+	want = []string{mixed[4], mixed[1], mixed[5], mixed[2], mixed[3], mixed[0]}
+	for shift := range mixed {
+		in := append(append([]string(nil), mixed[shift:]...), mixed[:shift]...)
+		got, _ = sortByColumn(in, make([]lineRef, len(in)), "v", false)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("sortByColumn of %v = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestTableRowFallback(t *testing.T) {
+	cols := []string{"level", "msg"}
+	widths := tableColumnWidths(cols, []string{`{"level":"info","msg":"x"}`})
+
+	row, ok := renderTableRow(`{"level":"info","msg":"hello"}`, cols, widths)
+	if !ok || row != "info "+tableColumnSeparator+"hello" {
+		t.Errorf("Unexpected table row %q", row)
+	}
+
+	raw := "2023-01-01 INFO not structured"
+	row, ok = renderTableRow(raw, cols, widths)
+	if ok || row != raw {
+		t.Errorf("Expected raw fallback for unstructured line, got %q", row)
+	}
+}
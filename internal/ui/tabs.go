@@ -0,0 +1,427 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fsnotify/fsnotify"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+// lineStore holds the lines read from one source. Every tab showing the
+// same file shares its store, so only the filtered view is per tab.
+type lineStore struct {
+	name     string
+	lines    []string
+	times    *timeIndex // Built on first use by Model.timeIndex
+	fileSize int64      // Offset the watcher reads new content from
+	watcher  *fsnotify.Watcher
+	streamer *Streamer
+	file     *os.File // A large file being streamed in, closed with the store
+	// generated marks reports built by lv (diffs, traces) rather than read
+	// from a source.
+	generated bool
+
+	rate  ingestRate // Lines received per second, for the footer
+	ended bool       // The stream reached its end
+	err   error      // Last error reading or watching the source
+
+	proc   *process // The command lv runs, for lv -- cmd
+	stderr []int    // Indexes of the lines it wrote to stderr, ascending
+
+	listener *Listener   // The socket lines arrive on, for lv --listen
+	glob     *globSource // The files of a directory or glob, merged
+	trace    *traceTab   // The ID a trace tab collects lines of
+
+	// Reading stopped because paused views buffered enough lines.
+	heldStreams []*Streamer
+	heldWatch   bool
+}
+
+// newLineStore starts streaming reader (when set) and watching the file.
+func newLineStore(name string, lines []string, reader io.Reader, cfg config.Config) *lineStore {
+	s := &lineStore{name: name, lines: lines}
+	if reader != nil {
+		s.streamer = NewStreamerWithConfig(reader, streamerConfig(cfg, name))
+	}
+	if info, err := os.Stat(name); err == nil {
+		s.fileSize = info.Size()
+	}
+	if watcher, _ := fsnotify.NewWatcher(); watcher != nil {
+		if watcher.Add(name) == nil {
+			s.watcher = watcher
+		} else {
+			watcher.Close() // Not a file, e.g. stdin
+		}
+	}
+	return s
+}
+
+// wait returns the commands that deliver the store's next lines.
+func (s *lineStore) wait() tea.Cmd {
+	var cmds []tea.Cmd
+	if s.watcher != nil {
+		cmds = append(cmds, WaitForFileChange(s.watcher, s.name, s.fileSize))
+	}
+	if s.streamer != nil {
+		cmds = append(cmds, WaitForStream(s.streamer))
+	}
+	if s.proc != nil {
+		cmds = append(cmds, WaitForStream(s.proc.out), WaitForStream(s.proc.errs), s.proc.wait())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (s *lineStore) close() {
+	if s.watcher != nil {
+		s.watcher.Close()
+	}
+	if s.proc != nil {
+		s.proc.stop()
+	}
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	if s.glob != nil {
+		s.glob.Close()
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+}
+
+// views lists every pane of every tab, the focused one first.
+func (m *Model) views() []*Model {
+	views := m.panes()
+	for i := range m.tabs {
+		if i == m.activeTab {
+			continue
+		}
+		views = append(views, &m.tabs[i])
+		for j := range m.tabs[i].split {
+			views = append(views, &m.tabs[i].split[j])
+		}
+	}
+	return views
+}
+
+// stores lists the line store of every pane of every tab once.
+func (m Model) stores() []*lineStore {
+	var list []*lineStore
+	for _, v := range m.views() {
+		if !containsStore(list, v.store) {
+			list = append(list, v.store)
+		}
+	}
+	return list
+}
+
+func containsStore(list []*lineStore, s *lineStore) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// findStore returns the store of any tab that match accepts.
+func (m Model) findStore(match func(*lineStore) bool) *lineStore {
+	for _, s := range m.stores() {
+		if match(s) {
+			return s
+		}
+	}
+	return nil
+}
+
+// receiveLines appends lines to a store. The panes on screen show them right
+// away; other tabs catch up when they are selected. The command is that of
+// any alert the lines fire.
+func (m *Model) receiveLines(s *lineStore, lines []string) tea.Cmd {
+	first := len(s.lines)
+	s.lines = append(s.lines, lines...)
+	s.rate.add(statsNow(), len(lines))
+	for _, p := range m.panes() {
+		if p.store != s {
+			continue
+		}
+		p.syncStore()
+		if p.following {
+			p.yOffset = max(0, len(p.filteredLines)-p.pageHeight())
+		}
+	}
+	return tea.Batch(m.checkAlerts(s, first), m.feedTraces(s, lines))
+}
+
+// syncStore brings the view up to date with lines the store received while
+// the tab was in the background. A paused view stays as it is.
+func (m *Model) syncStore() {
+	if m.paused {
+		return
+	}
+	if n := len(m.originalLines); n < len(m.store.lines) {
+		m.showAppendedLines(m.store.lines[n:])
+		m.bookmarkAlerts(n)
+	}
+}
+
+// tabCount is the number of open tabs.
+func (m Model) tabCount() int {
+	return max(1, len(m.tabs))
+}
+
+// selectTab makes tab i the active one. The view being left is saved as is,
+// split panes included; the window state carries over to the new one.
+func (m *Model) selectTab(i int) {
+	if len(m.tabs) < 2 {
+		return
+	}
+	i = (i%len(m.tabs) + len(m.tabs)) % len(m.tabs)
+	if i == m.activeTab {
+		return
+	}
+
+	tabs := m.tabs
+	current := *m
+	current.tabs = nil
+	tabs[m.activeTab] = current
+
+	next := tabs[i]
+	next.tabs, next.activeTab = tabs, i
+	next.takeWindowState(*m)
+	next.layoutPanes()
+	for _, p := range next.panes() {
+		p.syncStore()
+		if p.following {
+			p.yOffset = max(0, len(p.filteredLines)-p.pageHeight())
+		}
+	}
+	if next.showTimeline {
+		next.generateTimeline()
+	}
+	next.skipUndoRecord = true
+	*m = next
+}
+
+// newTab opens a tab after the current one and selects it. With no path it
+// duplicates the current view; a file that is already open reuses its lines.
+// The command starts watching a newly opened file.
+func (m *Model) newTab(path string) (tea.Cmd, error) {
+	var tab Model
+	var cmd tea.Cmd
+	if path == "" {
+		tab = *m
+		tab.split = nil
+		tab.undoStack, tab.redoStack = nil, nil
+		tab.bookmarks = copyBookmarks(m.bookmarks)
+		tab.bookmarkNotes = copyNotes(m.bookmarkNotes)
+		tab.detailFolds = make(map[string]bool)
+	} else {
+		store, err := m.openStore(path)
+		if err != nil {
+			return nil, err
+		}
+		if !containsStore(m.stores(), store) {
+			cmd = store.wait()
+		}
+		tab = newModelWithStore(store, m.cfg)
+		tab.history = m.history
+	}
+	m.insertTab(tab)
+	return cmd, nil
+}
+
+// insertTab adds tab after the current one and selects it.
+func (m *Model) insertTab(tab Model) {
+	if len(m.tabs) == 0 {
+		m.tabs = []Model{{}} // Slot of the active tab, filled by selectTab
+	}
+	at := m.activeTab + 1
+	m.tabs = append(m.tabs[:at:at], append([]Model{tab}, m.tabs[at:]...)...)
+	m.selectTab(at)
+}
+
+// openStore returns the store of an open file, or reads the file into a
+// new one.
+func (m Model) openStore(path string) (*lineStore, error) {
+	path = expandHome(path)
+	if s := m.findStore(func(s *lineStore) bool { return samePath(s.name, path) }); s != nil {
+		return s, nil
+	}
+	if IsWatchPattern(path) {
+		return newGlobStore(path, m.cfg)
+	}
+	lines, file, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return newLineStore(path, lines, nil, m.cfg), nil
+	}
+	s := newLineStore(path, nil, file, m.cfg)
+	s.file = file
+	return s, nil
+}
+
+// LargeFileThreshold is the size above which a file is streamed in rather
+// than read whole, to avoid startup stalls and memory spikes.
+const LargeFileThreshold = 10 * 1024 * 1024 // 10MB
+
+// ReadFile returns the lines of a file, or for one over LargeFileThreshold
+// the open file to stream them from, which the caller closes.
+func ReadFile(path string) ([]string, *os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if info.Size() > LargeFileThreshold {
+		return nil, f, nil
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return splitFileLines(string(data)), nil, nil
+}
+
+// closeTab closes the active tab, keeping at least one open. A store no
+// other tab uses stops being watched.
+func (m *Model) closeTab() {
+	if len(m.tabs) < 2 {
+		m.statusMsg = "Cannot close the last tab"
+		return
+	}
+	closing, at := m.store, m.activeTab
+	if at == 0 {
+		m.selectTab(1)
+	} else {
+		m.selectTab(at - 1)
+	}
+	m.tabs = append(m.tabs[:at:at], m.tabs[at+1:]...)
+	if m.activeTab > at {
+		m.activeTab--
+	}
+	if len(m.tabs) == 1 {
+		m.tabs, m.activeTab = nil, 0
+	}
+	if !containsStore(m.stores(), closing) {
+		closing.close()
+	}
+}
+
+// AddFileTabs opens each path in a tab of its own after the current one,
+// leaving the current tab selected. Init starts watching them.
+func (m Model) AddFileTabs(paths []string) (Model, error) {
+	first := m.activeTab
+	for _, p := range paths {
+		if _, err := m.newTab(p); err != nil {
+			return m, err
+		}
+	}
+	m.selectTab(first)
+	m.skipUndoRecord = false
+	return m, nil
+}
+
+// splitFileLines splits file content into lines, dropping \r line endings.
+func splitFileLines(content string) []string {
+	lines := splitIncomingContent(content)
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	return lines
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+func copyBookmarks(b map[int]struct{}) map[int]struct{} {
+	out := make(map[int]struct{}, len(b))
+	for k := range b {
+		out[k] = struct{}{}
+	}
+	return out
+}
+
+func copyNotes(n map[int]string) map[int]string {
+	out := make(map[int]string, len(n))
+	for k, v := range n {
+		out[k] = v
+	}
+	return out
+}
+
+// handleKeySequence runs two-key bindings such as gt and gT. The first key
+// still does its own job (g goes to the top); when the pair completes, the
+// position from before it is restored so the tab being left keeps it.
+func (m *Model) handleKeySequence(msg tea.KeyMsg) bool {
+	prefix := m.pendingKey
+	m.pendingKey = ""
+	if prefix != "" {
+		seq := keyMsgFromString(prefix + msg.String())
+		var step int
+		switch {
+		case key.Matches(seq, m.keys.NextTab):
+			step = 1
+		case key.Matches(seq, m.keys.PrevTab):
+			step = -1
+		}
+		// With one tab there is nothing to switch to, so the second key
+		// keeps its own meaning.
+		if step != 0 && len(m.tabs) > 1 {
+			m.yOffset, m.cursor = m.pendingPos[0], m.pendingPos[1]
+			m.selectTab(m.activeTab + step)
+			return true
+		}
+	}
+
+	s := msg.String()
+	for _, b := range []key.Binding{m.keys.NextTab, m.keys.PrevTab} {
+		for _, k := range b.Keys() {
+			if len(k) > len(s) && strings.HasPrefix(k, s) {
+				m.pendingKey = s
+				m.pendingPos = [2]int{m.yOffset, m.cursor}
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// tabTitle labels a tab with its file and filter.
+func (m Model) tabTitle() string {
+	title := filepath.Base(m.filename)
+	if m.filterText != "" {
+		title += " /" + m.filterText
+	}
+	return title
+}
+
+// tabBarView renders the open tabs, the active one boxed like the title.
+func (m Model) tabBarView() string {
+	parts := make([]string, len(m.tabs))
+	for i, t := range m.tabs {
+		if i == m.activeTab {
+			parts[i] = titleStyle.Render(fmt.Sprintf("%d:%s", i+1, m.tabTitle()))
+		} else {
+			parts[i] = mutedStyle.Render(fmt.Sprintf(" %d:%s ", i+1, t.tabTitle()))
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Center, parts...)
+}
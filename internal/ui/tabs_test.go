@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTabsKeepTheirOwnView(t *testing.T) {
+	lines := numberedLines(50)
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = pressKeys(m, "1", "0", "G") // Line 10 in the first tab
+	m = runCommandLine(m, "tabnew")
+	m = runCommandLine(m, "filter ERROR")
+	if m.tabCount() != 2 || m.activeTab != 1 {
+		t.Fatalf("Expected a second, active tab, got %d tabs (active %d)", m.tabCount(), m.activeTab)
+	}
+	if &m.tabs[0].store.lines[0] != &m.store.lines[0] {
+		t.Error("Expected tabs on the same file to share the line store")
+	}
+
+	m = pressKeys(m, "g", "t")
+	if m.activeTab != 0 || m.filterText != "" || m.filteredLines[m.cursor] != "INFO line 10" {
+		t.Fatalf("Expected gt to restore the first tab at line 10, got tab %d on %q", m.activeTab, m.filteredLines[m.cursor])
+	}
+	if len(m.undoStack) != 0 {
+		t.Error("Expected switching tabs not to be recorded as a filter change")
+	}
+
+	m = pressKeys(m, "g", "T")
+	if m.activeTab != 1 || m.filterText != "ERROR" {
+		t.Errorf("Expected gT to go back to the filtered tab, got tab %d %q", m.activeTab, m.filterText)
+	}
+	if !strings.Contains(m.headerView(), "2:test.log /ERROR") {
+		t.Errorf("Expected the tab bar to label the filtered tab, got %q", stripAnsi(m.headerView()))
+	}
+
+	m = runCommandLine(m, "tabclose")
+	if m.tabCount() != 1 || m.filterText != "" {
+		t.Errorf("Expected closing to return to the first tab, got %d tabs %q", m.tabCount(), m.filterText)
+	}
+
+	// With one tab, g goes to the top and t keeps its own binding.
+	m = pressKeys(m, "g", "t")
+	if m.cursor != 0 || !m.showTimeline {
+		t.Errorf("Expected gt with one tab to go to the top and open the timeline, cursor %d", m.cursor)
+	}
+}
+
+func TestFileTabsCatchUpInBackground(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.log")
+	b := filepath.Join(dir, "b.log")
+	os.WriteFile(a, []byte("INFO a1\nINFO a2\n"), 0o644)
+	os.WriteFile(b, []byte("INFO b1\r\n"), 0o644)
+
+	m := InitialModel(a, []string{"INFO a1", "INFO a2"}, nil)
+	m, err := m.AddFileTabs([]string{b, a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.tabCount() != 3 || m.activeTab != 0 || len(m.stores()) != 2 {
+		t.Fatalf("Expected 3 tabs over 2 stores with the first selected, got %d tabs, %d stores", m.tabCount(), len(m.stores()))
+	}
+
+	updated, _ := m.Update(FileChangeMsg{Filename: b, NewContent: "INFO b2\n", NewOffset: 16})
+	m = updated.(Model)
+	if len(m.filteredLines) != 2 {
+		t.Error("Expected lines for a background file to leave the active tab alone")
+	}
+
+	m.selectTab(1)
+	if got := strings.Join(m.filteredLines, ","); got != "INFO b1,INFO b2" {
+		t.Errorf("Expected the file tab to catch up when selected, got %q", got)
+	}
+
+	if _, err := m.AddFileTabs([]string{filepath.Join(dir, "missing.log")}); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestOpenStoreStreamsLargeFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.log")
+	line := "INFO " + strings.Repeat("x", 1019) + "\n"
+	os.WriteFile(path, []byte(strings.Repeat(line, LargeFileThreshold/len(line)+1)), 0o644)
+
+	m := InitialModel("test.log", numberedLines(5), nil)
+	s, err := m.openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+	if s.streamer == nil || s.file == nil || len(s.lines) != 0 {
+		t.Errorf("Expected a large file to be streamed in, got %d lines read", len(s.lines))
+	}
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+// builtinPalettes are the named color schemes. Colors are downsampled by
+// lipgloss to whatever the terminal's color profile supports.
+var builtinPalettes = map[string]config.Palette{
+	"dark": {
+		Error:         "#FF5F5F",
+		Warn:          "#FFD75F",
+		Info:          "#5FFF87",
+		Debug:         "#87AFFF", // Pure blue is unreadable on dark backgrounds
+		Match:         "#FFD75F",
+		MatchText:     "#000000",
+		Selection:     "#555555",
+		SelectionText: "#FFFFFF",
+		JSONKey:       "#8BE9FD",
+		Header:        "62",
+		Footer:        "252",
+		Muted:         "240",
+	},
+	"light": {
+		Error:         "#D70000",
+		Warn:          "#AF5F00",
+		Info:          "#008700",
+		Debug:         "#005FD7",
+		Match:         "#5FAFFF", // Yellow highlights wash out on white
+		MatchText:     "#000000",
+		Selection:     "#BCBCBC",
+		SelectionText: "#000000",
+		JSONKey:       "#0087AF",
+		Header:        "25",
+		Footer:        "238",
+		Muted:         "245",
+	},
+	"solarized": {
+		Error:         "#DC322F",
+		Warn:          "#B58900",
+		Info:          "#859900",
+		Debug:         "#268BD2",
+		Match:         "#B58900",
+		MatchText:     "#002B36",
+		Selection:     "#073642",
+		SelectionText: "#EEE8D5",
+		JSONKey:       "#2AA198",
+		Header:        "#6C71C4",
+		Footer:        "#93A1A1",
+		Muted:         "#586E75",
+	},
+	"high-contrast": {
+		Error:         "9",
+		Warn:          "11",
+		Info:          "10",
+		Debug:         "14",
+		Match:         "11",
+		MatchText:     "0",
+		Selection:     "15",
+		SelectionText: "0",
+		JSONKey:       "14",
+		Header:        "15",
+		Footer:        "15",
+		Muted:         "7",
+	},
+}
+
+// resolveTheme picks the palette named in cfg, honoring NO_COLOR. ok is false
+// for the colorless theme.
+func resolveTheme(cfg config.Config) (config.Palette, bool) {
+	if os.Getenv("NO_COLOR") != "" {
+		return config.Palette{}, false
+	}
+	name := cfg.Theme
+	if user, found := cfg.Themes[name]; found {
+		base, ok := builtinPalette(user.Base)
+		if !ok {
+			return config.Palette{}, false
+		}
+		return mergePalette(base, user), true
+	}
+	return builtinPalette(name)
+}
+
+func builtinPalette(name string) (config.Palette, bool) {
+	switch name {
+	case "none":
+		return config.Palette{}, false
+	case "", "auto":
+		if lipgloss.HasDarkBackground() {
+			name = "dark"
+		} else {
+			name = "light"
+		}
+	}
+	p, ok := builtinPalettes[name]
+	if !ok {
+		p = builtinPalettes["dark"]
+	}
+	return p, true
+}
+
+// mergePalette overlays the non-empty colors of user onto base.
+func mergePalette(base, user config.Palette) config.Palette {
+	pick := func(b, u string) string {
+		if u != "" {
+			return u
+		}
+		return b
+	}
+	return config.Palette{
+		Error:         pick(base.Error, user.Error),
+		Warn:          pick(base.Warn, user.Warn),
+		Info:          pick(base.Info, user.Info),
+		Debug:         pick(base.Debug, user.Debug),
+		Match:         pick(base.Match, user.Match),
+		MatchText:     pick(base.MatchText, user.MatchText),
+		Selection:     pick(base.Selection, user.Selection),
+		SelectionText: pick(base.SelectionText, user.SelectionText),
+		JSONKey:       pick(base.JSONKey, user.JSONKey),
+		Header:        pick(base.Header, user.Header),
+		Footer:        pick(base.Footer, user.Footer),
+		Muted:         pick(base.Muted, user.Muted),
+	}
+}
+
+// applyTheme sets the package styles from the theme in cfg.
+func applyTheme(cfg config.Config) {
+	p, colored := resolveTheme(cfg)
+	if !colored {
+		applyNoColorTheme()
+		return
+	}
+
+	fg := func(c string) lipgloss.Style { return lipgloss.NewStyle().Foreground(lipgloss.Color(c)) }
+
+	errorStyle = fg(p.Error).Bold(true)
+	warnStyle = fg(p.Warn).Bold(true)
+	infoStyleLog = fg(p.Info).Bold(true)
+	debugStyle = fg(p.Debug).Bold(true)
+
+	matchStyle = lipgloss.NewStyle().Background(lipgloss.Color(p.Match)).Foreground(lipgloss.Color(p.MatchText))
+	selectedStyle = lipgloss.NewStyle().Background(lipgloss.Color(p.Selection)).Foreground(lipgloss.Color(p.SelectionText))
+	jsonKeyStyle = fg(p.JSONKey)
+
+	titleStyle = baseTitleStyle.BorderForeground(lipgloss.Color(p.Header))
+	infoStyle = baseInfoStyle.BorderForeground(lipgloss.Color(p.Header))
+	footerStyle = fg(p.Footer)
+	helpKeyStyle = fg(p.JSONKey)
+
+	mutedStyle = fg(p.Muted)
+	repeatBadgeStyle = mutedStyle.Italic(true)
+	foldSummaryStyle = mutedStyle.Italic(true)
+	detailBorderStyle = mutedStyle
+	detailParsedStyle = mutedStyle.Italic(true)
+	detailCollapsedHint = mutedStyle
+	stderrStyle = fg(p.Error)
+}
+
+// applyNoColorTheme relies on attributes only (bold, reverse, underline) so
+// matches and selections stay visible without color.
+func applyNoColorTheme() {
+	plain := lipgloss.NewStyle()
+
+	errorStyle = plain.Bold(true)
+	warnStyle = plain.Bold(true)
+	infoStyleLog = plain
+	debugStyle = plain
+	matchStyle = plain.Reverse(true)
+	selectedStyle = plain.Reverse(true)
+	jsonKeyStyle = plain
+	titleStyle = baseTitleStyle
+	infoStyle = baseInfoStyle
+	footerStyle = plain
+	helpKeyStyle = plain.Bold(true)
+	mutedStyle = plain
+	repeatBadgeStyle = plain.Italic(true)
+	foldSummaryStyle = plain.Italic(true)
+	detailBorderStyle = plain
+	detailParsedStyle = plain.Italic(true)
+	detailCollapsedHint = plain
+	stderrStyle = plain.Italic(true)
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestApplyThemeNamed(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Cleanup(func() { applyConfig(config.Default()) })
+
+	cfg := config.Default()
+	cfg.Theme = "light"
+	applyTheme(cfg)
+	if got := debugStyle.GetForeground(); got != lipgloss.Color(builtinPalettes["light"].Debug) {
+		t.Errorf("Expected light debug color, got %v", got)
+	}
+	if got := matchStyle.GetBackground(); got != lipgloss.Color(builtinPalettes["light"].Match) {
+		t.Errorf("Expected light match color, got %v", got)
+	}
+}
+
+func TestApplyThemeUserPalette(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Cleanup(func() { applyConfig(config.Default()) })
+
+	cfg := config.Default()
+	cfg.Theme = "mine"
+	cfg.Themes = map[string]config.Palette{
+		"mine": {Base: "solarized", Error: "#123456"},
+	}
+	applyTheme(cfg)
+
+	if got := errorStyle.GetForeground(); got != lipgloss.Color("#123456") {
+		t.Errorf("Expected user error color, got %v", got)
+	}
+	if got := warnStyle.GetForeground(); got != lipgloss.Color(builtinPalettes["solarized"].Warn) {
+		t.Errorf("Expected unset colors to come from the base theme, got %v", got)
+	}
+}
+
+func TestApplyThemeNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Cleanup(func() { applyConfig(config.Default()) })
+
+	cfg := config.Default()
+	cfg.Theme = "dark"
+	applyTheme(cfg)
+
+	if _, isNoColor := matchStyle.GetForeground().(lipgloss.NoColor); !isNoColor {
+		t.Error("Expected no foreground color with NO_COLOR set")
+	}
+	if !matchStyle.GetReverse() || !selectedStyle.GetReverse() {
+		t.Error("Expected matches and selections to fall back to reverse video")
+	}
+}
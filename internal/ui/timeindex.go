@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// noTime marks lines before the first timestamp of the log.
+const noTime = math.MinInt64
+
+// timeIndex caches the timestamp of every original line so time jumps and
+// date filters don't re-parse the log. Lines without a timestamp (stack
+// traces, wrapped messages) take the time of the record they continue.
+//
+// Logs are mostly sorted, but not always: merged sources and clock skew put
+// some lines out of order. maxUpTo and minFrom are monotonic envelopes over
+// the times, so binary searches on them are exact even then: every line
+// before the first maxUpTo >= t is older than t, and every line from the
+// first minFrom >= t on is at least t. Only the lines in between need a
+// per-line check.
+type timeIndex struct {
+	times   []int64 // UnixNano of the line's record, or noTime
+	maxUpTo []int64 // max(times[:i+1])
+	minFrom []int64 // min(times[i:])
+	first   int     // first line with a timestamp, len(times) when none
+}
+
+// timeIndex returns the index over the tab's lines, building it on first
+// use and extending it as lines are appended. Tabs on the same source share it.
+func (m *Model) timeIndex() *timeIndex {
+	return m.store.timeIndex()
+}
+
+func (s *lineStore) timeIndex() *timeIndex {
+	if s.times == nil {
+		s.times = &timeIndex{}
+	}
+	s.times.extend(s.lines)
+	return s.times
+}
+
+// extend indexes the lines not seen yet.
+func (ix *timeIndex) extend(lines []string) {
+	seen := len(ix.times)
+	if seen == len(lines) {
+		return
+	}
+	for i := seen; i < len(lines); i++ {
+		t := int64(noTime)
+		if i > 0 {
+			t = ix.times[i-1]
+		}
+		if parsed, ok := extractDate(lines[i]); ok && parsed.Year() > 1677 && parsed.Year() < 2262 {
+			t = parsed.UnixNano()
+		}
+		if t == noTime {
+			ix.first = i + 1
+		}
+		ix.times = append(ix.times, t)
+		if i > 0 && ix.maxUpTo[i-1] > t {
+			ix.maxUpTo = append(ix.maxUpTo, ix.maxUpTo[i-1])
+		} else {
+			ix.maxUpTo = append(ix.maxUpTo, t)
+		}
+		ix.minFrom = append(ix.minFrom, t)
+	}
+
+	// New lines can only lower the suffix minimum of the lines before them;
+	// in a sorted log this stops right after the new lines.
+	low := int64(math.MaxInt64)
+	for j := len(ix.times) - 1; j >= 0; j-- {
+		low = min(low, ix.times[j])
+		if j < seen && ix.minFrom[j] <= low {
+			break
+		}
+		ix.minFrom[j] = low
+	}
+}
+
+// at returns the time of line i.
+func (ix *timeIndex) at(i int) (int64, bool) {
+	t := ix.times[i]
+	return t, t != noTime
+}
+
+// firstAtOrAfter returns the first line whose time is at least t, or
+// len(times) when there is none.
+func (ix *timeIndex) firstAtOrAfter(t int64) int {
+	return sort.Search(len(ix.maxUpTo), func(i int) bool { return ix.maxUpTo[i] >= t })
+}
+
+// span narrows a date filter to the lines [lo, hi) that can match. Lines
+// before the first timestamp are outside the index and always kept.
+func (ix *timeIndex) span(start, end *time.Time) (lo, hi int) {
+	lo, hi = ix.first, len(ix.times)
+	if start != nil {
+		lo = max(lo, ix.firstAtOrAfter(start.UnixNano()))
+	}
+	if end != nil {
+		e := end.UnixNano()
+		hi = sort.Search(len(ix.minFrom), func(i int) bool { return ix.minFrom[i] > e })
+	}
+	return lo, max(lo, hi)
+}
+
+// inRange reports whether line i passes the date filter.
+func (ix *timeIndex) inRange(i int, start, end *time.Time) bool {
+	t, ok := ix.at(i)
+	if !ok {
+		return true
+	}
+	if start != nil && t < start.UnixNano() {
+		return false
+	}
+	return end == nil || t <= end.UnixNano()
+}
+
+// latest is the newest time in the log.
+func (ix *timeIndex) latest() (time.Time, bool) {
+	if len(ix.maxUpTo) == 0 || ix.maxUpTo[len(ix.maxUpTo)-1] == noTime {
+		return time.Time{}, false
+	}
+	return time.Unix(0, ix.maxUpTo[len(ix.maxUpTo)-1]).In(timeLocation), true
+}
+
+// jumpToTime moves to the first visible record at or after target.
+func (m *Model) jumpToTime(target time.Time) {
+	row := m.rowAtTime(target.UnixNano())
+	if row >= len(m.filteredRefs) {
+		m.statusMsg = fmt.Sprintf("No lines at or after %s", target.Format("2006-01-02 15:04:05"))
+		return
+	}
+	m.setCursor(row)
+	m.yOffset = max(0, min(row, len(m.filteredLines)-m.pageHeight()))
+}
+
+// rowAtTime is the first visible row at or after t, or len(filteredRefs).
+func (m *Model) rowAtTime(t int64) int {
+	ix := m.timeIndex()
+	if m.tableMode && m.tableSortCol != "" {
+		// Sorted by a column: rows are not in file order.
+		row, best := len(m.filteredRefs), int64(math.MaxInt64)
+		for i, ref := range m.filteredRefs {
+			if rt, ok := ix.at(ref.first); ok && rt >= t && rt < best {
+				row, best = i, rt
+			}
+		}
+		return row
+	}
+	line := ix.firstAtOrAfter(t)
+	row := sort.Search(len(m.filteredRefs), func(i int) bool { return m.filteredRefs[i].last >= line })
+	// Skip visible lines that are out of order and still too early.
+	for row < len(m.filteredRefs) {
+		if rt, _ := ix.at(m.filteredRefs[row].last); rt >= t {
+			break
+		}
+		row++
+	}
+	return row
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeIndexOutOfOrder(t *testing.T) {
+	lines := []string{
+		"starting up",
+		"2024-01-01 10:00:00 INFO a",
+		"2024-01-01 10:05:00 INFO b",
+		"  at continuation",
+		"2024-01-01 10:02:00 WARN late", // Out of order
+		"2024-01-01 10:10:00 INFO c",
+	}
+	ix := &timeIndex{}
+	ix.extend(lines[:3])
+	ix.extend(lines) // Appending must match a fresh build
+
+	fresh := &timeIndex{}
+	fresh.extend(lines)
+	for i := range lines {
+		if ix.minFrom[i] != fresh.minFrom[i] || ix.maxUpTo[i] != fresh.maxUpTo[i] {
+			t.Fatalf("Line %d: incremental index differs from a fresh build", i)
+		}
+	}
+	if ix.first != 1 {
+		t.Errorf("Expected the first timestamp on line 1, got %d", ix.first)
+	}
+	if ix.times[3] != ix.times[2] {
+		t.Error("Expected the continuation line to take its record's time")
+	}
+
+	at := func(clock string) int64 {
+		tm, _ := time.Parse("2006-01-02 15:04:05", "2024-01-01 "+clock)
+		return tm.UnixNano()
+	}
+	if got := ix.firstAtOrAfter(at("10:01:00")); got != 2 {
+		t.Errorf("Expected the first line at or after 10:01 to be 2, got %d", got)
+	}
+
+	start, end := time.Unix(0, at("10:01:00")), time.Unix(0, at("10:03:00"))
+	lo, hi := ix.span(&start, &end)
+	if lo > 4 || hi <= 4 {
+		t.Errorf("Expected the span [%d, %d) to include the late line", lo, hi)
+	}
+	if ix.inRange(2, &start, &end) || !ix.inRange(4, &start, &end) {
+		t.Error("Expected only the late line to be in range")
+	}
+}
+
+func TestJumpTimeMovesView(t *testing.T) {
+	var lines []string
+	for i := 0; i < 120; i++ {
+		lines = append(lines, fmt.Sprintf("2024-01-01 10:%02d:%02d INFO tick %d", i/60, i%60, i))
+	}
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = pressKeys(m, "J", "10:01:30", "enter")
+	if got := m.filteredLines[m.cursor]; got != lines[90] {
+		t.Fatalf("Expected the jump to land on 10:01:30, got %q", got)
+	}
+	if m.yOffset == 0 {
+		t.Error("Expected the jump to scroll the view")
+	}
+
+	m = pressKeys(m, "J", "11:00", "enter")
+	if m.statusMsg == "" {
+		t.Error("Expected a message when no line is late enough")
+	}
+}
+
+func TestDateFilterKeepsContinuationLines(t *testing.T) {
+	lines := []string{
+		"2024-01-01 10:00:00 ERROR early",
+		"  at early.frame",
+		"2024-01-01 10:05:00 ERROR kept",
+		"  at kept.frame",
+	}
+	m := InitialModel("test.log", lines, nil)
+	m = runCommandLine(m, "since 2024-01-01 10:01:00")
+	if len(m.filteredLines) != 2 || m.filteredLines[1] != "  at kept.frame" {
+		t.Errorf("Expected the kept record with its stack frame, got %q", m.filteredLines)
+	}
+}
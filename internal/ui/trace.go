@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+var (
+	// traceFields are the record fields that hold a trace ID, in order.
+	traceFields []string
+	// traceFieldRegex finds the same fields written as key=value or key: value
+	// in lines that do not parse as records.
+	traceFieldRegex *regexp.Regexp
+	// tracePatterns find a trace ID in any line; a group narrows the match.
+	tracePatterns []*regexp.Regexp
+
+	uuidRegex = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+)
+
+// setTraceRules compiles the configured trace fields and patterns.
+func setTraceRules(cfg config.Trace) {
+	traceFields = cfg.Fields
+	traceFieldRegex = nil
+	if len(cfg.Fields) > 0 {
+		names := make([]string, len(cfg.Fields))
+		for i, f := range cfg.Fields {
+			names[i] = regexp.QuoteMeta(f)
+		}
+		traceFieldRegex = regexp.MustCompile(`\b(?:` + strings.Join(names, "|") + `)["']?\s*[:=]\s*["']?([^\s"',;&}\]]+)`)
+	}
+	tracePatterns = nil
+	for _, p := range cfg.Patterns {
+		if re, err := regexp.Compile(p); err == nil {
+			tracePatterns = append(tracePatterns, re)
+		}
+	}
+}
+
+// traceID extracts the request, trace or correlation ID of a line.
+func traceID(line string) (string, bool) {
+	line = stripAnsi(line)
+	if rec, ok := parseRecord(line); ok {
+		for _, f := range traceFields {
+			if v, ok := rec.Get(f); ok && v != "" {
+				return v, true
+			}
+		}
+	}
+	if traceFieldRegex != nil {
+		if match := traceFieldRegex.FindStringSubmatch(line); match != nil {
+			return match[1], true
+		}
+	}
+	for _, re := range tracePatterns {
+		if match := re.FindStringSubmatch(line); match != nil {
+			if len(match) > 1 && match[1] != "" {
+				return match[1], true
+			}
+			return match[0], true
+		}
+	}
+	if id := uuidRegex.FindString(line); id != "" {
+		return id, true
+	}
+	return "", false
+}
+
+// containsID reports whether id occurs in line as a whole token, so that
+// req_id=42 does not match 142 or 42ms.
+func containsID(line, id string) bool {
+	isWord := func(b byte) bool {
+		return b == '_' || b == '-' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+	}
+	for from := 0; ; {
+		i := strings.Index(line[from:], id)
+		if i < 0 {
+			return false
+		}
+		start, end := from+i, from+i+len(id)
+		if (start == 0 || !isWord(line[start-1])) && (end == len(line) || !isWord(line[end])) {
+			return true
+		}
+		from = start + 1
+	}
+}
+
+// traceTab is what a trace tab's store collects: lines carrying the ID that
+// arrive after the tab opened are added at its end, in the order they arrive.
+type traceTab struct {
+	id string
+	// tagged is settled when the tab opens, so that the lines added later
+	// look like the first ones: with several sources open, every line is
+	// tagged with the file it came from.
+	tagged bool
+}
+
+// tag marks a line with its source when the trace is tagged.
+func (t *traceTab) tag(s *lineStore, line string) string {
+	if t.tagged {
+		return fmt.Sprintf("[%s] %s", filepath.Base(s.name), line)
+	}
+	return line
+}
+
+// feedTraces adds the lines s just received to every open trace whose ID
+// they carry.
+func (m *Model) feedTraces(s *lineStore, lines []string) tea.Cmd {
+	if s.generated {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, t := range m.stores() {
+		if t.trace == nil {
+			continue
+		}
+		var hits []string
+		for _, line := range lines {
+			if containsID(line, t.trace.id) {
+				hits = append(hits, line)
+			}
+		}
+		if len(hits) == 0 {
+			continue
+		}
+		for i, line := range hits {
+			hits[i] = t.trace.tag(s, line)
+		}
+		cmds = append(cmds, m.receiveLines(t, hits))
+	}
+	return tea.Batch(cmds...)
+}
+
+// followTrace collects the lines carrying id from every open source into a
+// new tab, merged in time order, and appends those that arrive later. With
+// more than one source open each line is tagged with the file it came from.
+// An empty id is taken from the cursor line.
+func (m *Model) followTrace(id string) {
+	if id == "" {
+		if m.cursor >= len(m.filteredRefs) {
+			return
+		}
+		var ok bool
+		if id, ok = traceID(m.store.lines[m.filteredRefs[m.cursor].first]); !ok {
+			m.statusMsg = "No trace ID on this line"
+			return
+		}
+	}
+
+	type hit struct {
+		time   int64
+		source *lineStore
+		line   string
+	}
+	var hits []hit
+	var sources []*lineStore
+	open := 0
+	for _, s := range m.stores() {
+		if s.generated {
+			continue
+		}
+		open++
+		ix := s.timeIndex()
+		for i, line := range s.lines {
+			if containsID(line, id) {
+				hits = append(hits, hit{ix.times[i], s, line})
+			}
+		}
+		if len(hits) > 0 && hits[len(hits)-1].source == s {
+			sources = append(sources, s)
+		}
+	}
+	if len(hits) == 0 {
+		m.statusMsg = fmt.Sprintf("No lines carry %s", id)
+		return
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].time < hits[j].time })
+
+	trace := &traceTab{id: id, tagged: open > 1}
+	lines := make([]string, len(hits))
+	for i, h := range hits {
+		lines[i] = trace.tag(h.source, h.line)
+	}
+	tab := newModelWithStore(&lineStore{name: "trace " + id, lines: lines, generated: true, trace: trace}, m.cfg)
+	tab.history = m.history
+	if m.redact {
+		tab.redact = true
+		tab.applyFilters(false)
+	}
+	if rule, err := newSessionHighlight(regexp.QuoteMeta(id), 0); err == nil {
+		tab.sessionHighlights = []highlightRule{rule}
+	}
+	m.insertTab(tab)
+	m.statusMsg = fmt.Sprintf("Trace %s: %d lines from %d sources (:tabclose to go back)", id, len(lines), len(sources))
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rajeshkannanramakrishnan/lv/internal/config"
+)
+
+func TestTraceID(t *testing.T) {
+	cfg := config.Default().Trace
+	cfg.Patterns = []string{`\bcid:(\w+)`}
+	setTraceRules(cfg)
+	defer setTraceRules(config.Default().Trace)
+
+	tests := []struct{ line, want string }{
+		{`{"level":"info","msg":"done","request_id":"r-17"}`, "r-17"},
+		{`level=info msg=done traceId=abc123 user=9`, "abc123"},
+		{`2024-01-01 10:00:00 INFO cid:k9 accepted`, "k9"},
+		{`2024-01-01 10:00:00 INFO job 6F1C2E0A-9B7D-4C21-8E3F-0123456789AB done`, "6F1C2E0A-9B7D-4C21-8E3F-0123456789AB"},
+		{`2024-01-01 10:00:00 INFO nothing to see`, ""},
+	}
+	for _, tt := range tests {
+		if got, _ := traceID(tt.line); got != tt.want {
+			t.Errorf("traceID(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if !containsID("req_id=42 took 3ms", "42") || containsID("req_id=142 took 42ms", "42") {
+		t.Error("Expected IDs to match whole tokens only")
+	}
+}
+
+func TestFollowTraceAcrossTabs(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.log")
+	db := filepath.Join(dir, "db.log")
+	apiLines := []string{
+		"2024-01-01 10:00:01 INFO request_id=r-1 GET /orders",
+		"2024-01-01 10:00:02 INFO request_id=r-2 GET /users",
+		"2024-01-01 10:00:05 ERROR request_id=r-1 500 after 4s",
+	}
+	os.WriteFile(api, []byte(strings.Join(apiLines, "\n")+"\n"), 0o644)
+	os.WriteFile(db, []byte("2024-01-01 10:00:03 WARN request_id=r-1 slow query\n2024-01-01 10:00:04 INFO request_id=r-11 ok\n"), 0o644)
+
+	m := InitialModel(api, apiLines, nil)
+	m, err := m.AddFileTabs([]string{db})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m = pressKeys(m, "*")
+	if m.tabCount() != 3 || m.activeTab != 1 {
+		t.Fatalf("Expected the trace in a new tab, got %d tabs (%s)", m.tabCount(), m.statusMsg)
+	}
+	want := []string{
+		"[api.log] 2024-01-01 10:00:01 INFO request_id=r-1 GET /orders",
+		"[db.log] 2024-01-01 10:00:03 WARN request_id=r-1 slow query",
+		"[api.log] 2024-01-01 10:00:05 ERROR request_id=r-1 500 after 4s",
+	}
+	if got := strings.Join(m.filteredLines, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("Expected the r-1 lines of both files in time order, got:\n%s", got)
+	}
+	if !strings.Contains(m.statusMsg, "3 lines from 2 sources") {
+		t.Errorf("Unexpected status %q", m.statusMsg)
+	}
+
+	// Lines carrying the ID that arrive later are added to the trace.
+	late := "2024-01-01 10:00:06 INFO request_id=r-1 retried\n2024-01-01 10:00:07 INFO request_id=r-12 ok\n"
+	updated, _ := m.Update(FileChangeMsg{Filename: db, NewContent: late, NewOffset: 200})
+	m = updated.(Model)
+	if got := m.filteredLines[len(m.filteredLines)-1]; len(m.filteredLines) != 4 || got != "[db.log] 2024-01-01 10:00:06 INFO request_id=r-1 retried" {
+		t.Errorf("Expected the new r-1 line at the end of the trace, got %q", m.filteredLines)
+	}
+
+	// Following from the trace tab does not count its own lines.
+	m = runCommandLine(m, "trace r-2")
+	if len(m.filteredLines) != 1 || m.filteredLines[0] != "[api.log] "+apiLines[1] {
+		t.Errorf("Expected only the r-2 line, tagged as two files are open, got %q", m.filteredLines)
+	}
+
+	// Lines from the other file that arrive later are tagged alike.
+	updated, _ = m.Update(FileChangeMsg{Filename: db, NewContent: "2024-01-01 10:00:08 INFO request_id=r-2 done\n", NewOffset: 300})
+	m = updated.(Model)
+	if got := m.filteredLines[len(m.filteredLines)-1]; got != "[db.log] 2024-01-01 10:00:08 INFO request_id=r-2 done" {
+		t.Errorf("Expected the late line tagged with its file, got %q", m.filteredLines)
+	}
+}
+
+func TestFollowTraceKeepsRedaction(t *testing.T) {
+	m := InitialModel("test.log", []string{"INFO request_id=r-1 login password=hunter2"}, nil)
+	m = pressKeys(m, "P", "*")
+	if m.tabCount() != 2 || !m.redact || strings.Contains(m.filteredLines[0], "hunter2") {
+		t.Errorf("Expected the trace tab to stay redacted, got %q", m.filteredLines)
+	}
+}
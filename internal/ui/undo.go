@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxUndoStates = 100
+
+// viewState is what undo and redo restore: the filters plus the scroll
+// position they were viewed at.
+type viewState struct {
+	filterText string
+	regexMode  bool
+	levels     [4]bool // error, warn, info, debug
+	startDate  *time.Time
+	endDate    *time.Time
+	fold       bool
+
+	yOffset int
+	cursor  int
+}
+
+func (m Model) viewState() viewState {
+	return viewState{
+		filterText: m.filterText,
+		regexMode:  m.regexMode,
+		levels:     [4]bool{m.showError, m.showWarn, m.showInfo, m.showDebug},
+		startDate:  m.startDate,
+		endDate:    m.endDate,
+		fold:       m.foldStackTraces,
+		yOffset:    m.yOffset,
+		cursor:     m.cursor,
+	}
+}
+
+// sameFilters ignores the scroll position: moving around is not undoable.
+func (s viewState) sameFilters(o viewState) bool {
+	return s.filterText == o.filterText &&
+		s.regexMode == o.regexMode &&
+		s.levels == o.levels &&
+		sameTime(s.startDate, o.startDate) &&
+		sameTime(s.endDate, o.endDate) &&
+		s.fold == o.fold
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// recordViewChange pushes the state from before a key press when the key
+// changed the filters. A new change drops the redo history.
+func (m *Model) recordViewChange(before viewState) {
+	if before.sameFilters(m.viewState()) {
+		return
+	}
+	m.undoStack = append(m.undoStack, before)
+	if len(m.undoStack) > maxUndoStates {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndoStates:]
+	}
+	m.redoStack = nil
+}
+
+// undo restores the previous view state; redo reapplies an undone one.
+func (m *Model) undo() { m.stepHistory(&m.undoStack, &m.redoStack, "Undo") }
+func (m *Model) redo() { m.stepHistory(&m.redoStack, &m.undoStack, "Redo") }
+
+func (m *Model) stepHistory(from, to *[]viewState, verb string) {
+	if len(*from) == 0 {
+		m.statusMsg = fmt.Sprintf("%s: nothing to %s", verb, strings.ToLower(verb))
+		return
+	}
+	target := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+
+	current := m.viewState()
+	*to = append(*to, current)
+	m.restoreViewState(target)
+	m.statusMsg = fmt.Sprintf("%s: %s", verb, describeViewChange(current, target))
+}
+
+// restoreViewState applies the filters of s and returns to its position.
+func (m *Model) restoreViewState(s viewState) {
+	// Bookmarks and notes move with their lines to the rows of the restored
+	// view, where those lines are shown.
+	marks := make(map[int]string, len(m.bookmarks)) // Store line -> note
+	for row := range m.bookmarks {
+		if row < len(m.filteredRefs) {
+			marks[m.filteredRefs[row].first] = m.bookmarkNotes[row]
+		}
+	}
+
+	m.filterText = s.filterText
+	m.regexMode = s.regexMode
+	m.showError, m.showWarn, m.showInfo, m.showDebug = s.levels[0], s.levels[1], s.levels[2], s.levels[3]
+	m.startDate, m.endDate = s.startDate, s.endDate
+	m.foldStackTraces = s.fold
+	m.applyFilters(false)
+
+	m.bookmarks = make(map[int]struct{}, len(marks))
+	m.bookmarkNotes = make(map[int]string)
+	for line, note := range marks {
+		if row, ok := m.storeLineRow(line); ok {
+			m.bookmarks[row] = struct{}{}
+			if note != "" {
+				m.bookmarkNotes[row] = note
+			}
+		}
+	}
+	// The selection and cached layout belong to the old rows.
+	m.selectionStart, m.selectionEnd = nil, nil
+	m.visualMode = visualNone
+	m.layoutCache = make(map[int][]string)
+
+	m.yOffset = max(0, min(s.yOffset, len(m.filteredLines)-m.pageHeight()))
+	m.setCursor(s.cursor)
+}
+
+// describeViewChange summarizes what differs between two states for the
+// footer, e.g. `filter "" → "timeout", WARN shown`.
+func describeViewChange(from, to viewState) string {
+	var parts []string
+	if from.filterText != to.filterText {
+		parts = append(parts, fmt.Sprintf("filter %q → %q", from.filterText, to.filterText))
+	}
+	if from.regexMode != to.regexMode {
+		parts = append(parts, "regex "+onOff(to.regexMode))
+	}
+	for i, name := range []string{"ERROR", "WARN", "INFO", "DEBUG"} {
+		if from.levels[i] != to.levels[i] {
+			if to.levels[i] {
+				parts = append(parts, name+" shown")
+			} else {
+				parts = append(parts, name+" hidden")
+			}
+		}
+	}
+	if !sameTime(from.startDate, to.startDate) {
+		parts = append(parts, "start "+formatBound(to.startDate))
+	}
+	if !sameTime(from.endDate, to.endDate) {
+		parts = append(parts, "end "+formatBound(to.endDate))
+	}
+	if from.fold != to.fold {
+		parts = append(parts, "fold "+onOff(to.fold))
+	}
+	if len(parts) == 0 {
+		return "scroll position"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatBound(t *time.Time) string {
+	if t == nil {
+		return "cleared"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// trackUndo runs a key press through update and records the previous state
+// when the key changed the filters. Undo and redo themselves are skipped.
+func (m Model) trackUndo(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	before := m.viewState()
+	updated, cmd := m.update(msg)
+	next, ok := updated.(Model)
+	if !ok {
+		return updated, cmd
+	}
+	if next.skipUndoRecord {
+		next.skipUndoRecord = false
+		return next, cmd
+	}
+	next.recordViewChange(before)
+	return next, cmd
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoRedoFilterChanges(t *testing.T) {
+	lines := []string{"INFO a", "ERROR b", "WARN c", "ERROR d"}
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = pressKeys(m, "/", "ERROR", "enter")
+	m = pressKeys(m, "2")
+	m = pressKeys(m, "j") // Scrolling alone is not recorded
+	if len(m.undoStack) != 2 {
+		t.Fatalf("Expected 2 undo states, got %d", len(m.undoStack))
+	}
+
+	m = pressKeys(m, "c") // Clear wipes the filter
+	if m.filterText != "" {
+		t.Fatal("Expected c to clear the filter")
+	}
+
+	m = pressKeys(m, "u")
+	if m.filterText != "ERROR" || m.cursor != 1 {
+		t.Errorf("Expected undo to restore the filter and cursor, got %q at %d", m.filterText, m.cursor)
+	}
+	if !strings.Contains(m.statusMsg, `filter "" → "ERROR"`) {
+		t.Errorf("Expected the footer to describe the change, got %q", m.statusMsg)
+	}
+
+	m = pressKeys(m, "u")
+	if !m.showWarn {
+		t.Error("Expected the second undo to show WARN again")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updated.(Model)
+	if m.showWarn || m.filterText != "ERROR" {
+		t.Errorf("Expected redo to hide WARN again, got warn=%v filter=%q", m.showWarn, m.filterText)
+	}
+
+	m = pressKeys(m, "3", "j") // A new change drops the redo history
+	if len(m.redoStack) != 0 {
+		t.Errorf("Expected an empty redo stack, got %d", len(m.redoStack))
+	}
+}
+
+func TestUndoNothing(t *testing.T) {
+	m := InitialModel("test.log", []string{"a"}, nil)
+	m = pressKeys(m, "u")
+	if !strings.Contains(m.statusMsg, "nothing to undo") {
+		t.Errorf("Expected a nothing-to-undo message, got %q", m.statusMsg)
+	}
+}
+
+func TestUndoKeepsBookmarks(t *testing.T) {
+	lines := []string{"INFO a", "ERROR b", "WARN c", "ERROR d"}
+	m := InitialModel("test.log", lines, nil)
+	m.viewport.Height = 10
+
+	m = pressKeys(m, "/", "ERROR", "enter")
+	m = pressKeys(m, "j")
+	m.bookmarkCursor("retry storm")
+
+	m = pressKeys(m, "u")
+	if m.filterText != "" {
+		t.Fatal("Expected undo to clear the filter")
+	}
+	if _, ok := m.bookmarks[3]; !ok || m.bookmarkNotes[3] != "retry storm" || len(m.bookmarks) != 1 {
+		t.Errorf("Expected the bookmark and note to move to ERROR d, got %v %v", m.bookmarks, m.bookmarkNotes)
+	}
+
+	m = pressKeyMsg(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+	if _, ok := m.bookmarks[1]; !ok || m.filterText != "ERROR" {
+		t.Errorf("Expected redo to keep the bookmark too, got %v", m.bookmarks)
+	}
+}
@@ -10,6 +10,7 @@ import (
 )
 
 type FileChangeMsg struct {
+	Filename   string
 	NewContent string
 	NewOffset  int64
 	Error      error
@@ -62,7 +63,7 @@ func WaitForFileChange(watcher *fsnotify.Watcher, filename string, currentOffset
 				if !ok {
 					return nil
 				}
-				return FileChangeMsg{Error: err}
+				return FileChangeMsg{Filename: filename, Error: err}
 			}
 		}
 	}
@@ -71,23 +72,24 @@ func WaitForFileChange(watcher *fsnotify.Watcher, filename string, currentOffset
 func readNewContent(filename string, offset int64) tea.Msg {
 	f, err := os.Open(filename)
 	if err != nil {
-		return FileChangeMsg{Error: err}
+		return FileChangeMsg{Filename: filename, Error: err}
 	}
 	defer f.Close()
 
 	_, err = f.Seek(offset, 0)
 	if err != nil {
-		return FileChangeMsg{Error: err}
+		return FileChangeMsg{Filename: filename, Error: err}
 	}
 
 	content, err := io.ReadAll(f)
 	if err != nil {
-		return FileChangeMsg{Error: err}
+		return FileChangeMsg{Filename: filename, Error: err}
 	}
 
     newOffset := offset + int64(len(content))
 
 	return FileChangeMsg{
+		Filename:   filename,
 		NewContent: string(content),
 		NewOffset:  newOffset,
 	}